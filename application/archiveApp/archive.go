@@ -0,0 +1,49 @@
+package archiveApp
+
+import (
+	"FSchedule/domain/archive"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+type ImportDTO struct {
+	Key string // 归档的Key
+}
+
+type ImportResultDTO struct {
+	TaskCount int // 导入的任务数量
+	LogCount  int // 导入的日志数量
+}
+
+// List 任务组的归档
+func List(name string, archiveRepository archive.Repository) collections.List[archive.FileVO] {
+	if !archiveRepository.IsEnable() {
+		exception.ThrowWebException(403, "未开启归档")
+	}
+	lst, err := archiveRepository.ToList(name)
+	if err != nil {
+		exception.ThrowWebExceptionf(500, "读取归档失败：%s", err.Error())
+	}
+	return lst
+}
+
+// Import 将归档的任务及日志重新导入数据库
+func Import(dto ImportDTO, actor string, ip string, archiveRepository archive.Repository, taskGroupRepository taskGroup.Repository, taskLogRepository taskLog.Repository, auditRepository audit.Repository) ImportResultDTO {
+	if !archiveRepository.IsEnable() {
+		exception.ThrowWebException(403, "未开启归档")
+	}
+	lstTask, lstLog, err := archiveRepository.Load(dto.Key)
+	if err != nil {
+		exception.ThrowWebExceptionf(403, "读取归档失败：%s", err.Error())
+	}
+	taskGroupRepository.ImportTasks(lstTask)
+	taskLogRepository.Import(lstLog)
+
+	result := ImportResultDTO{TaskCount: lstTask.Count(), LogCount: lstLog.Count()}
+	auditRepository.Add(audit.New(enum.Admin, "ImportArchive", actor, ip, dto.Key, nil, result))
+	return result
+}
@@ -0,0 +1,42 @@
+package auditApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/collections"
+	"time"
+)
+
+type QueryDTO struct {
+	Types     []enum.AuditType // 审计类型
+	Actor     string           // 操作人
+	Ip        string           // 来源IP
+	Target    string           // 操作对象（任务组名称、客户端ID）
+	StartAt   int64            // 开始时间（毫秒时间戳）
+	EndAt     int64            // 结束时间（毫秒时间戳）
+	PageSize  int              // 每页数量
+	PageIndex int              // 页码
+}
+
+// Query 查询审计记录
+func Query(dto QueryDTO, auditRepository audit.Repository) collections.PageList[audit.DomainObject] {
+	filter := audit.FilterVO{
+		Types:  dto.Types,
+		Actor:  dto.Actor,
+		Ip:     dto.Ip,
+		Target: dto.Target,
+	}
+	if dto.StartAt > 0 {
+		filter.StartAt = time.UnixMilli(dto.StartAt)
+	}
+	if dto.EndAt > 0 {
+		filter.EndAt = time.UnixMilli(dto.EndAt)
+	}
+	if dto.PageSize < 1 {
+		dto.PageSize = 20
+	}
+	if dto.PageIndex < 1 {
+		dto.PageIndex = 1
+	}
+	return auditRepository.ToPageList(filter, dto.PageSize, dto.PageIndex)
+}
@@ -0,0 +1,23 @@
+package clientApp
+
+import (
+	"FSchedule/domain/client"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+// List 客户端列表
+func List(clientRepository client.Repository) collections.List[client.DomainObject] {
+	return clientRepository.ToList().OrderBy(func(item client.DomainObject) any {
+		return item.Id
+	}).ToList()
+}
+
+// Info 客户端详情
+func Info(id int64, clientRepository client.Repository) client.DomainObject {
+	do := clientRepository.ToEntity(id)
+	if do.Id == 0 {
+		exception.ThrowWebExceptionf(404, "客户端：%d 不存在", id)
+	}
+	return do
+}
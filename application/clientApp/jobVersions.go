@@ -0,0 +1,72 @@
+package clientApp
+
+import (
+	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"sort"
+)
+
+type JobVersionDTO struct {
+	Name          string               // 任务组名称
+	Ver           int                  // 任务组当前版本
+	DispatchVer   int                  // 优先调度的版本（回滚后为回滚的版本）
+	VersionPolicy enum.VersionPolicy   // 调度客户端版本的策略
+	Clients       []JobVersionClientVO // 注册了该任务的客户端
+}
+
+type JobVersionClientVO struct {
+	Id        int64             // 客户端ID
+	Name      string            // 客户端名称
+	Ip        string            // 客户端IP
+	Ver       int               // 客户端注册的任务版本
+	Status    enum.ClientStatus // 客户端状态
+	AllowVer  bool              // 按当前策略，是否会调度给该客户端
+	IsCurrent bool              // 是否为优先调度的版本
+}
+
+// JobVersions 每个任务组下，客户端注册的版本
+func JobVersions(clientRepository client.Repository, taskGroupRepository taskGroup.Repository) []JobVersionDTO {
+	jobs := make(map[string]*JobVersionDTO)
+	taskGroups := make(map[string]taskGroup.DomainObject)
+	for _, taskGroupDO := range taskGroupRepository.ToList().ToArray() {
+		taskGroups[taskGroupDO.Name] = taskGroupDO
+		jobs[taskGroupDO.Name] = &JobVersionDTO{
+			Name:          taskGroupDO.Name,
+			Ver:           taskGroupDO.Ver,
+			DispatchVer:   taskGroupDO.DispatchVer(),
+			VersionPolicy: taskGroupDO.VersionPolicy,
+		}
+	}
+
+	for _, clientDO := range clientRepository.ToList().ToArray() {
+		for _, jobVO := range clientDO.Jobs.ToArray() {
+			job, exists := jobs[jobVO.Name]
+			if !exists {
+				continue
+			}
+			taskGroupDO := taskGroups[jobVO.Name]
+			job.Clients = append(job.Clients, JobVersionClientVO{
+				Id:        clientDO.Id,
+				Name:      clientDO.Name,
+				Ip:        clientDO.Ip,
+				Ver:       jobVO.Ver,
+				Status:    clientDO.Status,
+				AllowVer:  taskGroupDO.AllowVer(jobVO.Ver),
+				IsCurrent: jobVO.Ver == taskGroupDO.DispatchVer(),
+			})
+		}
+	}
+
+	lst := make([]JobVersionDTO, 0, len(jobs))
+	for _, job := range jobs {
+		sort.Slice(job.Clients, func(i, j int) bool {
+			return job.Clients[i].Ver > job.Clients[j].Ver
+		})
+		lst = append(lst, *job)
+	}
+	sort.Slice(lst, func(i, j int) bool {
+		return lst[i].Name < lst[j].Name
+	})
+	return lst
+}
@@ -1,10 +1,35 @@
 package clientApp
 
-import "FSchedule/domain/client"
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
+	"strconv"
+)
 
 // Logout 客户端下线
-func Logout(clientId int64, repository client.Repository) {
+func Logout(clientId int64, remoteIp string, repository client.Repository, auditRepository audit.Repository) {
 	clientDO := repository.ToEntity(clientId)
 	clientDO.Logout()
 	repository.Save(&clientDO)
+	auditRepository.Add(audit.New(enum.Logout, "Logout", clientDO.Name, remoteIp, strconv.FormatInt(clientId, 10), nil, nil))
+}
+
+// Disconnect 客户端长连接断开
+func Disconnect(clientId int64, serverId int64, repository client.Repository) {
+	clientDO := repository.ToEntity(clientId)
+	if clientDO.Disconnect(serverId) {
+		repository.Save(&clientDO)
+	}
+}
+
+// DisconnectByServer 服务端节点下线，连接在该节点上的客户端全部下线
+func DisconnectByServer(serverId int64, repository client.Repository) {
+	lst := repository.ToList()
+	for i := 0; i < lst.Count(); i++ {
+		clientDO := lst.Index(i)
+		if clientDO.Disconnect(serverId) {
+			repository.Save(&clientDO)
+		}
+	}
 }
@@ -1,46 +1,76 @@
 package clientApp
 
 import (
+	"FSchedule/application/taskGroupApp"
+	"FSchedule/domain/audit"
 	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
 	"FSchedule/domain/schedule"
 	"FSchedule/domain/taskGroup"
 	"github.com/farseer-go/collections"
 	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/mapper"
+	"strconv"
 )
 
 type RegistryDTO struct {
-	Id   int64            `json:"ClientId"`   // 客户端ID
-	Name string           `json:"ClientName"` // 客户端名称
-	Ip   string           `json:"ClientIp"`   // 客户端IP
-	Port int              `json:"ClientPort"` // 客户端端口
-	Jobs []RegistryJobDTO `json:"ClientJobs"` // 客户端动态注册任务
+	Id       int64            `json:"ClientId"`   // 客户端ID
+	Name     string           `json:"ClientName"` // 客户端名称
+	Ip       string           `json:"ClientIp"`   // 客户端IP
+	Port     int              `json:"ClientPort"` // 客户端端口
+	Jobs     []RegistryJobDTO `json:"ClientJobs"` // 客户端动态注册任务
+	Mode     enum.ClientMode  `json:"ClientMode"` // 连接模式
+	ServerId int64            `json:"-"`          // 长连接所在的服务端节点
+	Tags     []string         `json:"ClientTags"` // 客户端标签
 }
 
 type RegistryJobDTO struct {
-	Name     string // 任务名称
-	Ver      int    // 任务版本
-	Caption  string // 任务标题
-	Cron     string // 任务执行表达式
-	StartAt  int64  // 任务开始时间
-	IsEnable bool   // 任务是否启用
+	Name              string                 // 任务名称
+	Ver               int                    // 任务版本
+	Caption           string                 // 任务标题
+	Cron              string                 // 任务执行表达式
+	StartAt           int64                  // 任务开始时间
+	IsEnable          bool                   // 任务是否启用
+	ConcurrencyPolicy enum.ConcurrencyPolicy // 任务执行中到达下一个周期时的处理策略
+	MaxParallel       int                    // 最多同时执行的任务数量（Allow策略，0不限制）
+	Priority          int                    // 优先级（排队时，数值越大越先调度）
+	Namespace         string                 // 命名空间（按命名空间限制同时执行的任务数量）
+	DataSchema        taskGroup.DataSchemaVO // 任务参数（Data）的JSON Schema
 }
 
 // Registry 客户端注册
-func Registry(dto RegistryDTO, clientRepository client.Repository, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository) {
+func Registry(dto RegistryDTO, remoteIp string, clientRepository client.Repository, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository, auditRepository audit.Repository) {
 	do := mapper.Single[client.DomainObject](dto)
 	do.Jobs = collections.NewList[client.JobVO]()
 	if do.IsNil() {
 		exception.ThrowWebException(403, "客户端ID、Name、IP、Port未完整传入")
 	}
+	if do.IsStream() && do.ServerId == 0 {
+		exception.ThrowWebException(403, "长连接模式需通过/api/stream/connect注册")
+	}
 
 	// 先推送任务信息再保存客户端
 	// 更新任务组
 	for _, jobDTO := range dto.Jobs {
+		if err := jobDTO.DataSchema.Check(); err != nil {
+			exception.ThrowWebExceptionf(403, "任务组：%s %s", jobDTO.Name, err.Error())
+		}
 		taskGroupDO := taskGroupRepository.ToEntity(jobDTO.Name)
-		taskGroupDO.UpdateVer(jobDTO.Name, jobDTO.Caption, jobDTO.Ver, jobDTO.Cron, jobDTO.StartAt, jobDTO.IsEnable)
+		beforeDO := taskGroupDO
+		// 当前参数不符合新的参数定义时不拒绝注册，由管理端修改参数
+		if err := taskGroupDO.UpdateVer(jobDTO.Name, jobDTO.Caption, jobDTO.Ver, jobDTO.Cron, jobDTO.StartAt, jobDTO.IsEnable, jobDTO.ConcurrencyPolicy, jobDTO.MaxParallel, jobDTO.Priority, jobDTO.Namespace, jobDTO.DataSchema); err != nil {
+			flog.Warningf("任务组：%s 当前参数不符合客户端注册的参数定义：%s", jobDTO.Name, err.Error())
+		}
 		if taskGroupDO.NeedSave {
 			taskGroupRepository.Save(taskGroupDO)
+
+			// 版本变更，保存历史定义
+			if taskGroupDO.Ver != beforeDO.Ver {
+				taskGroupApp.EnsureVersion(beforeDO, taskGroupRepository)
+				taskGroupRepository.AddVersion(taskGroupDO.ToVersion())
+				auditRepository.Add(audit.New(enum.UpdateVer, "UpdateVer", do.Name, remoteIp, taskGroupDO.Name, beforeDO, taskGroupDO))
+			}
 		}
 		do.Jobs.Add(mapper.Single[client.JobVO](jobDTO))
 	}
@@ -49,4 +79,5 @@ func Registry(dto RegistryDTO, clientRepository client.Repository, taskGroupRepo
 	do.Registry()
 	do.CheckOnline()
 	clientRepository.Save(&do)
+	auditRepository.Add(audit.New(enum.Registry, "Registry", do.Name, remoteIp, strconv.FormatInt(do.Id, 10), nil, dto))
 }
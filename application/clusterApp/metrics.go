@@ -0,0 +1,24 @@
+package clusterApp
+
+import (
+	"FSchedule/domain"
+	"fmt"
+	"strings"
+)
+
+// Metrics 当前节点的调度排队指标（Prometheus文本格式）
+func Metrics() string {
+	queued, dequeued, waitTime := domain.QueueStats()
+	var builder strings.Builder
+	writeMetric(&builder, "fschedule_pending_task_groups", "gauge", "排队等待调度的任务组数量", float64(domain.PendingCount()))
+	writeMetric(&builder, "fschedule_pending_max_wait_seconds", "gauge", "排队中的任务组最长的等待时间", float64(domain.PendingMaxWaitTime())/1000)
+	writeMetric(&builder, "fschedule_queued_total", "counter", "进入排队的次数", float64(queued))
+	writeMetric(&builder, "fschedule_dequeued_total", "counter", "结束排队的次数", float64(dequeued))
+	writeMetric(&builder, "fschedule_queue_wait_seconds_total", "counter", "结束排队的累计等待时间", float64(waitTime)/1000)
+	writeMetric(&builder, "fschedule_working_task_groups", "gauge", "当前节点负责调度的任务组数量", float64(len(domain.WorkingTaskGroups())))
+	return builder.String()
+}
+
+func writeMetric(builder *strings.Builder, name string, metricType string, help string, value float64) {
+	_, _ = fmt.Fprintf(builder, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+}
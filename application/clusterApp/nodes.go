@@ -0,0 +1,49 @@
+package clusterApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/exception"
+	"strconv"
+)
+
+type NodesDTO struct {
+	LeaderId int64                                     // 当前Master节点
+	Nodes    collections.List[serverNode.DomainObject] // 集群节点
+	Assign   map[string]int64                          // 任务组分配给的节点
+}
+
+// Nodes 集群节点及当前Master
+func Nodes(scheduleRepository schedule.Repository, serverNodeRepository serverNode.Repository) NodesDTO {
+	leaderId := scheduleRepository.GetLeaderId()
+	lst := serverNodeRepository.ToList().OrderBy(func(item serverNode.DomainObject) any {
+		return item.Id
+	}).ToList()
+	for i := 0; i < lst.Count(); i++ {
+		node := lst.Index(i)
+		node.SetLeader(leaderId)
+		lst.Set(i, node)
+	}
+	return NodesDTO{LeaderId: leaderId, Nodes: lst, Assign: scheduleRepository.GetAssign()}
+}
+
+// StepDown 强制当前Master让位，由集群重新选举
+func StepDown(actor string, ip string, scheduleRepository schedule.Repository, auditRepository audit.Repository) {
+	leaderId := scheduleRepository.GetLeaderId()
+	if leaderId == 0 {
+		exception.ThrowWebException(403, "当前没有Master节点")
+	}
+	// 由Master节点自己让位（先停止Master相关的任务，再释放锁）
+	if leaderId == fs.AppId {
+		scheduleRepository.StepDown()
+	} else {
+		_ = container.Resolve[core.IEvent]("ClusterStepDown").Publish(leaderId)
+	}
+	auditRepository.Add(audit.New(enum.Admin, "StepDown", actor, ip, strconv.FormatInt(leaderId, 10), nil, nil))
+}
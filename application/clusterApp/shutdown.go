@@ -0,0 +1,55 @@
+package clusterApp
+
+import (
+	"FSchedule/application/healthApp"
+	"FSchedule/domain"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+	"sync"
+	"time"
+)
+
+// 等待正在进行的调度完成的最长时间
+const stopMonitorTimeout = 5 * time.Second
+
+// 等待任务日志写入数据库的最长时间
+const flushTimeout = 10 * time.Second
+
+var shutdownOnce sync.Once
+
+// Shutdown 节点关闭时，将任务组、Master交由其它节点，并持久化未写入的数据
+func Shutdown() {
+	shutdownOnce.Do(func() {
+		if !container.IsRegister[schedule.Repository]() {
+			return
+		}
+		flog.Infof("节点：%d 开始关闭", fs.AppId)
+
+		// 不再接收流量、不再调度新的任务
+		healthApp.Closing()
+		if !domain.StopMonitor(stopMonitorTimeout) {
+			flog.Warningf("等待调度线程退出超时：%v", domain.WorkingTaskGroups())
+		}
+
+		// 释放调度锁、Master，通知其它节点立即接管
+		serverNode.IsLeaderNode = false
+		count := container.Resolve[schedule.Repository]().Release()
+		_ = container.Resolve[core.IEvent]("ScheduleRelease").Publish(fs.AppId)
+
+		// 写入队列中的任务日志、任务组数据
+		if !container.Resolve[taskLog.Repository]().Flush(flushTimeout) {
+			flog.Warning("等待任务日志写入超时")
+		}
+		container.Resolve[taskGroup.Repository]().Sync()
+
+		// 移除当前节点
+		container.Resolve[serverNode.Repository]().Remove(fs.AppId)
+		flog.Infof("节点：%d 已关闭，释放了%d个任务组", fs.AppId, count)
+	})
+}
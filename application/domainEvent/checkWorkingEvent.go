@@ -7,6 +7,7 @@ import (
 	"FSchedule/domain/taskGroup"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
 )
 
 // CheckWorkingEvent 检查进行中的任务
@@ -14,28 +15,86 @@ func CheckWorkingEvent(message any, _ core.EventArgs) {
 	do := message.(*domain.TaskGroupMonitor)
 	taskGroupRepository := container.Resolve[taskGroup.Repository]()
 	clientRepository := container.Resolve[client.Repository]()
-	clientCheck := container.Resolve[client.IClientCheck]()
 
 	if do.Task.Status != enum.Working {
 		return
 	}
 
+	// 并行执行中的任务，客户端下线了，则设为失败
+	if checkRunningTasks(do.DomainObject, taskGroupRepository, clientRepository) && !saveFenced(do.DomainObject, taskGroupRepository) {
+		return
+	}
+
+	// 客户端拉取的任务，租约过期后回收，重新调度
+	if do.Task.IsLeased() {
+		if do.Task.IsLeaseExpired() {
+			flog.Warningf("任务组：%s %d 租约已过期，重新调度", do.Name, do.Task.Id)
+			if !do.Task.ReclaimLease() {
+				return
+			}
+			saveFenced(do.DomainObject, taskGroupRepository)
+		}
+		return
+	}
+
 	// 得到当前处理的客户端
 	clientDO := do.GetClient()
 
 	// 客户端下线了
 	if clientDO == nil || clientDO.IsNil() || clientDO.IsOffline() {
-		do.ClientOffline()
-		taskGroupRepository.Save(*do.DomainObject)
+		if !do.ClientOffline() {
+			return
+		}
+		saveFenced(do.DomainObject, taskGroupRepository)
 		return
 	}
 
 	// 主动向客户端查询任务状态
-	dto, err := clientCheck.Status(clientDO, do.Task.Id)
+	dto, err := clientDO.ClientCheck().Status(clientDO, do.Task.Id)
 	if err != nil {
 		clientDO.UnSchedule()
 		clientRepository.Save(clientDO)
-	} else {
-		do.Report(dto.Status, dto.Data, dto.Progress, dto.RunSpeed, dto.NextTimespan, taskGroupRepository)
+		// 偶发的网络抖动不处理，连续无法访问时按下线处理，下一个任务从断点续跑
+		if !do.StatusFail() {
+			flog.Warningf("任务组：%s %d 查询客户端（%d）任务状态失败：%s", do.Name, do.Task.Id, clientDO.Id, err.Error())
+			return
+		}
+		if do.ClientOffline() {
+			saveFenced(do.DomainObject, taskGroupRepository)
+		}
+		return
+	}
+	do.StatusSuccess()
+	if do.Report(dto.Status, dto.Data, dto.Progress, dto.RunSpeed, dto.NextTimespan) {
+		saveFenced(do.DomainObject, taskGroupRepository)
+	}
+}
+
+// 检查并行执行中的任务（Allow策略）
+func checkRunningTasks(do *taskGroup.DomainObject, taskGroupRepository taskGroup.Repository, clientRepository client.Repository) bool {
+	isChange := false
+	for _, taskId := range do.RunningTaskIds {
+		taskEO := taskGroupRepository.GetTask(do.Name, taskId)
+		if taskEO.IsNull() {
+			continue
+		}
+
+		if !taskEO.IsFinish() {
+			clientDO := clientRepository.ToEntity(taskEO.Client.Id)
+			if !clientDO.IsNil() && !clientDO.IsOffline() {
+				continue
+			}
+			if !do.RunningTaskOffline(&taskEO) {
+				continue
+			}
+			if !saveTaskFenced(&taskEO, taskGroupRepository) {
+				return false
+			}
+		}
+
+		if do.RunningTaskFinish(taskEO) {
+			isChange = true
+		}
 	}
+	return isChange
 }
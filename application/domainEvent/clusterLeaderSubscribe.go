@@ -3,6 +3,9 @@ package domainEvent
 import (
 	"FSchedule/application/job"
 	"FSchedule/domain"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
 	"FSchedule/domain/serverNode"
 	"FSchedule/domain/taskGroup"
 	"github.com/farseer-go/fs"
@@ -12,6 +15,7 @@ import (
 	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/fs/parse"
 	"github.com/farseer-go/tasks"
+	"strconv"
 	"time"
 )
 
@@ -22,7 +26,13 @@ func ClusterLeaderSubscribe(message any, _ core.EventArgs) {
 	flog.Infof("选举%s为Master节点", flog.Red(leaderId))
 
 	// 当前节点是leader
-	if leaderId == fs.AppId {
+	if leaderId == fs.AppId && serverNode.LeaderContext.Err() == nil && container.Resolve[schedule.Repository]().CheckLeader() {
+		// Master相关的任务，失去Master后停止
+		leaderContext := serverNode.LeaderContext
+
+		// 记录选举结果
+		container.Resolve[audit.Repository]().Add(audit.New(enum.Election, "Election", fs.HostName, fs.AppIp, strconv.FormatInt(leaderId, 10), nil, serverNode.New()))
+
 		// 更新集群leader信息
 		serverNodeRepository := container.Resolve[serverNode.Repository]()
 		lst := serverNodeRepository.ToList()
@@ -35,24 +45,41 @@ func ClusterLeaderSubscribe(message any, _ core.EventArgs) {
 		// 同步任务组、任务数据
 		syncTime := configure.GetInt("FSchedule.DataSyncTime")
 		if syncTime > 0 {
-			tasks.RunNow("taskGroupSync", 60*time.Second, func(context *tasks.TaskContext) {
+			tasks.RunNow("taskGroupSync", 60*time.Second, leaderJob("taskGroupSync", func(context *tasks.TaskContext) {
 				container.Resolve[taskGroup.Repository]().Sync()
-			}, fs.Context)
+			}), leaderContext)
 		}
 
 		// 标记当前节点为Leader
-		domain.CheckOnline()
 		serverNode.IsLeaderNode = true
+		domain.CheckOnline()
 
 		// 移除30秒不活跃的
-		tasks.Run("ServerNodeTimeoutJob", 30*time.Second, job.ServerNodeTimeoutJob, fs.Context)
+		tasks.Run("ServerNodeTimeoutJob", 30*time.Second, leaderJob("ServerNodeTimeoutJob", job.ServerNodeTimeoutJob), leaderContext)
 
 		// 计算任务组的平均耗时
-		tasks.Run("SyncAvgSpeedJob", 30*time.Minute, job.SyncAvgSpeedJob, fs.Context)
+		tasks.Run("SyncAvgSpeedJob", 30*time.Minute, leaderJob("SyncAvgSpeedJob", job.SyncAvgSpeedJob), leaderContext)
 
 		// 自动清除历史任务记录
 		if configure.GetInt("FSchedule.ReservedTaskCount") > 0 {
-			tasks.Run("ClearHisTaskJob", 1*time.Hour, job.ClearHisTaskJob, fs.Context)
+			tasks.Run("ClearHisTaskJob", 1*time.Hour, leaderJob("ClearHisTaskJob", job.ClearHisTaskJob), leaderContext)
+		}
+
+		// 按保留策略清除过期的任务日志
+		tasks.Run("ClearTaskLogJob", 1*time.Hour, leaderJob("ClearTaskLogJob", job.ClearTaskLogJob), leaderContext)
+
+		// 将任务组均衡分配给集群节点
+		tasks.Run("BalanceJob", 10*time.Second, leaderJob("BalanceJob", job.BalanceJob), leaderContext)
+	}
+}
+
+// Master相关的任务，执行前校验令牌，失去Master（令牌过期）后不再执行
+func leaderJob(name string, fn func(context *tasks.TaskContext)) func(context *tasks.TaskContext) {
+	return func(context *tasks.TaskContext) {
+		if !container.Resolve[schedule.Repository]().CheckLeader() {
+			flog.Warningf("当前节点：%d 的Master令牌已过期，跳过：%s", fs.AppId, name)
+			return
 		}
+		fn(context)
 	}
 }
@@ -0,0 +1,20 @@
+package domainEvent
+
+import (
+	"FSchedule/domain/schedule"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/parse"
+)
+
+// ClusterStepDownSubscribe 管理端要求Master让位，由Master节点自己让位
+func ClusterStepDownSubscribe(message any, _ core.EventArgs) {
+	if parse.Convert(message, int64(0)) != fs.AppId {
+		return
+	}
+	if container.Resolve[schedule.Repository]().StepDown() {
+		flog.Warningf("当前节点：%d 收到让位请求，停止Master相关的任务并释放Master", fs.AppId)
+	}
+}
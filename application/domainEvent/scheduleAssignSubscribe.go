@@ -0,0 +1,17 @@
+package domainEvent
+
+import (
+	"FSchedule/domain"
+	"FSchedule/domain/schedule"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+)
+
+// ScheduleAssignSubscribe 任务组重新分配，交出分配给其它节点的任务组
+func ScheduleAssignSubscribe(_ any, _ core.EventArgs) {
+	assign := container.Resolve[schedule.Repository]().GetAssign()
+	if count := domain.Handoff(assign); count > 0 {
+		flog.Infof("任务组重新分配，当前节点交出%s个任务组", flog.Red(count))
+	}
+}
@@ -0,0 +1,17 @@
+package domainEvent
+
+import (
+	"FSchedule/domain/schedule"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/parse"
+)
+
+// ScheduleReleaseSubscribe 其它节点关闭，释放了调度锁、Master
+func ScheduleReleaseSubscribe(message any, _ core.EventArgs) {
+	if parse.Convert(message, int64(0)) == fs.AppId {
+		return
+	}
+	container.Resolve[schedule.Repository]().NotifyRelease()
+}
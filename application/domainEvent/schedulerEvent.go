@@ -1,10 +1,15 @@
 package domainEvent
 
 import (
+	"FSchedule/application/taskGroupApp"
 	"FSchedule/domain"
 	"FSchedule/domain/client"
 	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskEvent"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/tracing"
+	"fmt"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
 	"github.com/farseer-go/fs/flog"
@@ -20,13 +25,43 @@ func SchedulerEvent(message any, _ core.EventArgs) {
 	if do.Task.Status != enum.Scheduling {
 		return
 	}
+	span := tracing.Start(do.Task.TraceParent, "SchedulerEvent", tracing.Task(do.Name, do.Task.Id)...)
+	defer span.End()
 	taskGroupRepository := container.Resolve[taskGroup.Repository]()
 	clientRepository := container.Resolve[client.Repository]()
+	scheduleRepository := container.Resolve[schedule.Repository]()
+
+	// 调度锁已被其它节点接管，不再调度
+	if !scheduleRepository.CheckFence(do.Name) {
+		flog.Warningf("任务组：%s 调度锁的令牌已过期，停止调度", do.Name)
+		span.SetAttributes(tracing.String("fschedule.result", "fenced"))
+		return
+	}
+
+	// Secret参数只在下发给客户端时解密
+	data, err := do.Task.RevealData()
+	if err != nil {
+		_ = flog.Errorf("任务组：%s %d 解密Secret参数失败：%s", do.Name, do.Task.Id, err.Error())
+		span.SetAttributes(tracing.String("fschedule.result", "secret"))
+		do.ScheduleFail("解密Secret参数失败")
+		saveFenced(do.DomainObject, taskGroupRepository)
+		return
+	}
+
+	// 超过限流或没有可调度的客户端时排队，等待重新调度
+	if do.CanScheduler() && !do.TryDispatch() {
+		flog.Debugf("任务组：%s 超过限流，加入排队", do.Name)
+		span.SetAttributes(tracing.String("fschedule.result", "queue"))
+		do.Queue()
+		saveFenced(do.DomainObject, taskGroupRepository)
+		return
+	}
 
 	for {
 		if !do.CanScheduler() {
 			flog.Debugf("任务组：%s 无法调度，条件不满足，延迟：%d us", do.Name, time.Since(do.Task.StartAt).Microseconds())
-			do.ScheduleFail()
+			span.SetAttributes(tracing.String("fschedule.result", "fail"))
+			do.ScheduleFail("调度条件不满足")
 			return
 		}
 
@@ -34,29 +69,107 @@ func SchedulerEvent(message any, _ core.EventArgs) {
 		clientSchedule := do.PollingClient()
 		// 没有可调度的客户端
 		if clientSchedule == nil || clientSchedule.IsNil() {
-			flog.Debugf("任务组：%s 没有可调度的客户端，延迟：%d us", do.Name, time.Since(do.Task.StartAt).Microseconds())
-			do.ScheduleFail()
-			taskGroupRepository.Save(*do.DomainObject)
+			// 由拉取模式的客户端主动拉取任务
+			if do.HasPullClient() {
+				flog.Debugf("任务组：%s 等待客户端拉取，延迟：%d us", do.Name, time.Since(do.Task.StartAt).Microseconds())
+				span.SetAttributes(tracing.String("fschedule.result", "pull"))
+				saveFenced(do.DomainObject, taskGroupRepository)
+				return
+			}
+			// 客户端繁忙或拒绝调度，排队等待客户端恢复
+			flog.Debugf("任务组：%s 没有可调度的客户端，加入排队，延迟：%d us", do.Name, time.Since(do.Task.StartAt).Microseconds())
+			span.SetAttributes(tracing.String("fschedule.result", "queue"))
+			do.Queue()
+			saveFenced(do.DomainObject, taskGroupRepository)
 			return
 		}
 
+		// 同一次执行只下发一次（重试、故障转移时去重），按下发次数占用，中断的下发不阻塞之后的下发
+		idempotencyKey := do.Task.IdempotencyKey()
+		invoke, isClaim := scheduleRepository.ClaimInvoke(idempotencyKey, do.Task.Id, do.Task.Attempt+1)
+		if !isClaim {
+			// 已下发成功，恢复为执行中
+			if invoke.ClientId > 0 {
+				if invokeClient := do.RestoreClient(invoke.ClientId); invokeClient != nil {
+					flog.Warningf("任务组：%s %d 已下发给客户端（%d），不再重复下发", do.Name, do.Task.Id, invoke.ClientId)
+					span.SetAttributes(tracing.String("fschedule.result", "dedupe"), tracing.Int64("fschedule.client_id", invoke.ClientId))
+					if !do.Task.Restore(mapper.Single[taskGroup.ClientVO](invokeClient), invoke.Attempt) {
+						return
+					}
+					saveAndTaskFenced(do.DomainObject, taskGroupRepository)
+					return
+				}
+				// 客户端已不存在，重新下发
+				scheduleRepository.CompleteInvoke(idempotencyKey, invoke, false)
+			} else {
+				// 上一次下发中断（节点故障、失去调度锁），使用新的下发次数，被中断的下发的上报会被拒绝
+				flog.Warningf("任务组：%s %d 第%d次下发已中断，重新下发", do.Name, do.Task.Id, invoke.Attempt)
+				span.AddEvent("下发中断", tracing.Int("fschedule.attempt", invoke.Attempt))
+			}
+			do.Task.SkipAttempt(invoke.Attempt)
+			continue
+		}
+
 		// 分配客户端
-		do.SetClient(mapper.Single[taskGroup.ClientVO](clientSchedule))
+		if !do.SetClient(mapper.Single[taskGroup.ClientVO](clientSchedule)) {
+			span.SetAttributes(tracing.String("fschedule.result", "transit"))
+			scheduleRepository.CompleteInvoke(idempotencyKey, invoke, false)
+			return
+		}
+		do.SetClientVer(clientSchedule.JobVer(do.Name))
 
 		// 请求客户端
 		clientTask := mapper.Single[client.TaskEO](do.Task)
+		clientTask.Data = data
+		clientTask.TraceParent = span.TraceParent()
+		span.AddEvent("分配客户端", tracing.Int64("fschedule.client_id", clientSchedule.Id), tracing.String("fschedule.client_name", clientSchedule.Name))
 		flog.Debugf("任务组：%s %d 分配完客户端，立即调度，延迟：%d us", do.Name, do.Task.Id, time.Since(do.Task.StartAt).Microseconds())
-		if clientSchedule.Schedule(&clientTask) {
+		clientTask.IdempotencyKey = idempotencyKey
+		invoke.ClientId, invoke.Attempt = clientSchedule.Id, do.Task.Attempt
+		isSuccess := clientSchedule.Schedule(&clientTask)
+		scheduleRepository.CompleteInvoke(idempotencyKey, invoke, isSuccess)
+		if isSuccess {
 			// 调度成功
+			span.SetAttributes(tracing.String("fschedule.result", "success"), tracing.Int64("fschedule.client_id", clientSchedule.Id))
 			clientRepository.Save(clientSchedule)
-			taskGroupRepository.SaveAndTask(*do.DomainObject)
+			saveAndTaskFenced(do.DomainObject, taskGroupRepository)
 			return
 		}
 		// 调度失败
 		clientRepository.Save(clientSchedule)
-		do.ScheduleFail()
-		taskGroupRepository.Save(*do.DomainObject)
+		do.ScheduleFail(fmt.Sprintf("下发给客户端（%d）失败", clientSchedule.Id))
+		if !saveFenced(do.DomainObject, taskGroupRepository) {
+			return
+		}
 
 		time.Sleep(100 * time.Millisecond)
 	}
 }
+
+// 调度线程保存任务组，保存成功后发布状态变更事件（当前节点已失去调度锁时记录日志，返回false）
+func saveFenced(do *taskGroup.DomainObject, taskGroupRepository taskGroup.Repository) bool {
+	events := do.PopEvents()
+	return checkSaved(taskGroupRepository.SaveFenced(*do), events)
+}
+
+// 调度线程保存任务组、任务，保存成功后发布状态变更事件（当前节点已失去调度锁时记录日志，返回false）
+func saveAndTaskFenced(do *taskGroup.DomainObject, taskGroupRepository taskGroup.Repository) bool {
+	events := do.PopEvents()
+	return checkSaved(taskGroupRepository.SaveAndTaskFenced(*do), events)
+}
+
+// 调度线程保存任务，保存成功后发布状态变更事件（当前节点已失去调度锁时记录日志，返回false）
+func saveTaskFenced(taskEO *taskGroup.TaskEO, taskGroupRepository taskGroup.Repository) bool {
+	events := taskEO.PopEvents()
+	return checkSaved(taskGroupRepository.SaveTaskFenced(*taskEO), events)
+}
+
+// 写入成功时发布状态变更事件，失败时记录日志
+func checkSaved(err error, events []taskEvent.DomainObject) bool {
+	if err != nil {
+		flog.Warning(err)
+		return false
+	}
+	taskGroupApp.PublishEvents(events)
+	return true
+}
@@ -1,8 +1,11 @@
 package domainEvent
 
 import (
+	"FSchedule/domain/audit"
 	"FSchedule/domain/enum"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/tracing"
+	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
 	"github.com/farseer-go/fs/flog"
@@ -15,10 +18,16 @@ func TaskFinishEvent(message any, _ core.EventArgs) {
 	if !do.Task.IsFinish() {
 		return
 	}
+	span := tracing.Start(do.Task.TraceParent, "TaskFinishEvent", append(tracing.Task(do.Name, do.Task.Id), tracing.String("fschedule.status", do.Task.Status.String()), tracing.Int64("fschedule.run_speed", do.Task.RunSpeed))...)
+	defer span.End()
 
 	taskGroupRepository := container.Resolve[taskGroup.Repository]()
 	// 先保存任务内容
-	taskGroupRepository.SaveTask(do.Task)
+	if !saveTaskFenced(&do.Task, taskGroupRepository) {
+		return
+	}
+	// 金丝雀发布中，根据新版本的执行结果自动全量发布、回滚
+	canaryReport(do, taskGroupRepository)
 	// 成功才要计算下一个周期
 	if do.Task.Status == enum.Success {
 		do.CalculateNextAtByCron()
@@ -26,5 +35,26 @@ func TaskFinishEvent(message any, _ core.EventArgs) {
 	// 任务初始化
 	do.CreateTask()
 	flog.Debugf("任务组：%s %d 任务完成，下次执行时间：%s\n", do.Name, do.Task.Id, do.Task.StartAt.Format(time.DateTime))
-	taskGroupRepository.SaveAndTask(*do)
+	saveAndTaskFenced(do, taskGroupRepository)
+}
+
+// 金丝雀发布中，根据新版本的执行结果自动全量发布、回滚
+func canaryReport(do *taskGroup.DomainObject, taskGroupRepository taskGroup.Repository) {
+	beforeDO := *do
+	promote, rollback := do.CanaryReport()
+	switch {
+	case promote:
+		flog.Infof("任务组：%s 金丝雀发布成功，版本：%d 全量发布", do.Name, do.Ver)
+		container.Resolve[audit.Repository]().Add(audit.New(enum.UpdateVer, "CanaryPromote", fs.HostName, fs.AppIp, do.Name, beforeDO, *do))
+	case rollback:
+		// 没有历史定义时，保持当前定义，只回滚客户端版本
+		version := taskGroupRepository.GetVersion(do.Name, do.Ver-1)
+		if version.IsNil() {
+			version = do.ToVersion()
+			version.Ver = do.Ver - 1
+		}
+		do.Rollback(version)
+		flog.Warningf("任务组：%s 金丝雀发布失败，版本：%d 回滚到：%d", do.Name, do.Ver, version.Ver)
+		container.Resolve[audit.Repository]().Add(audit.New(enum.UpdateVer, "CanaryRollback", fs.HostName, fs.AppIp, do.Name, beforeDO, *do))
+	}
 }
@@ -3,7 +3,9 @@ package domainEvent
 import (
 	"FSchedule/domain"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLive"
 	"encoding/json"
+	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
 )
 
@@ -16,4 +18,8 @@ func TaskGroupUpdateSubscribe(message any, _ core.EventArgs) {
 	}
 
 	domain.MonitorTaskGroupPush(&taskGroupDO)
+	// 唤醒当前节点等待拉取的客户端
+	domain.PullNoticePush(&taskGroupDO)
+	// 每个节点都会收到任务组的更新，只需推送给当前节点的订阅者
+	container.Resolve[taskLive.IHub]().Push(taskLive.NewStatus(taskGroupDO))
 }
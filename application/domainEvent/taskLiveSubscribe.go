@@ -0,0 +1,17 @@
+package domainEvent
+
+import (
+	"FSchedule/domain/taskLive"
+	"encoding/json"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+)
+
+// TaskLiveSubscribe 任务日志（Redis订阅），推送给当前节点的订阅者
+func TaskLiveSubscribe(message any, _ core.EventArgs) {
+	var events []taskLive.EventVO
+	if err := json.Unmarshal([]byte(message.(string)), &events); err != nil {
+		return
+	}
+	container.Resolve[taskLive.IHub]().Push(events...)
+}
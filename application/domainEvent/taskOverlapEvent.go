@@ -0,0 +1,48 @@
+package domainEvent
+
+import (
+	"FSchedule/domain"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+)
+
+// TaskOverlapEvent 任务执行中，到达了下一个执行周期
+func TaskOverlapEvent(message any, _ core.EventArgs) {
+	do := message.(*domain.TaskGroupMonitor)
+	if !do.Task.IsWorking() {
+		return
+	}
+	taskGroupRepository := container.Resolve[taskGroup.Repository]()
+
+	switch do.ConcurrencyPolicy {
+	case enum.Replace:
+		// 终止执行中的任务，本次周期重新开始
+		do.KillTask()
+		flog.Infof("任务组：%s %d 执行超过一个周期，终止并重新开始", do.Name, do.Task.Id)
+		if !do.Replace() {
+			return
+		}
+		saveFenced(do.DomainObject, taskGroupRepository)
+		return
+	case enum.Allow:
+		if do.CanParallel() {
+			flog.Infof("任务组：%s %d 执行超过一个周期，并行执行新的任务", do.Name, do.Task.Id)
+			do.Parallel()
+			saveAndTaskFenced(do.DomainObject, taskGroupRepository)
+			return
+		}
+	case enum.Delay:
+		// 默认策略：任务完成后再计算下一个执行周期
+		return
+	}
+
+	// 跳过本次周期，立即写入跳过记录（连续跳过的周期合并为同一条记录）
+	skipTask := do.Skip()
+	flog.Infof("任务组：%s %d 执行超过一个周期，已跳过%d个周期", do.Name, do.Task.Id, do.SkipCount)
+	if saveTaskFenced(&skipTask, taskGroupRepository) {
+		saveFenced(do.DomainObject, taskGroupRepository)
+	}
+}
@@ -0,0 +1,12 @@
+package domainEvent
+
+import (
+	"FSchedule/domain/taskEvent"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+)
+
+// TaskTransitEvent 任务状态变更，记录到状态变更历史
+func TaskTransitEvent(message any, _ core.EventArgs) {
+	container.Resolve[taskEvent.Repository]().Add(message.(taskEvent.DomainObject))
+}
@@ -0,0 +1,25 @@
+package domainEvent
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskEvent"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+)
+
+// TaskWorkingEvent 任务状态变更，更新集群中执行中任务的统计（客户端并发、命名空间配额）
+func TaskWorkingEvent(message any, _ core.EventArgs) {
+	event := message.(taskEvent.DomainObject)
+	scheduleRepository := container.Resolve[schedule.Repository]()
+	switch {
+	case event.ToStatus == enum.Working:
+		do := container.Resolve[taskGroup.Repository]().ToEntity(event.Name)
+		if do.Task.Id == event.TaskId {
+			scheduleRepository.AddWorking(event.TaskId, do.Task.Client.Id, do.Namespace)
+		}
+	case event.FromStatus == enum.Working:
+		scheduleRepository.RemoveWorking(event.TaskId)
+	}
+}
@@ -0,0 +1,122 @@
+package healthApp
+
+import (
+	"FSchedule/domain/archive"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/timingWheel"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 时间轮超过该时长没有触发，认为已停止
+const tickTimeout = 5 * time.Second
+
+// 时间轮最后一次触发的时间（UnixMilli）
+var lastTickAt atomic.Int64
+
+// 启动完成（所有AddInitCallback已执行）
+var isReady atomic.Bool
+
+// 节点关闭中
+var isClosing atomic.Bool
+
+type HealthDTO struct {
+	TickAt time.Time // 时间轮最后一次触发的时间
+}
+
+type ClusterDTO struct {
+	TickAt    time.Time // 时间轮最后一次触发的时间
+	IsLeader  bool      // 当前节点是否为Master
+	HasLeader bool      // 集群是否已选出Master
+}
+
+type ReadyDTO struct {
+	Checks []string // 已通过的检查项
+}
+
+// Ready 启动完成，开始探测时间轮（需在最后一个AddInitCallback中调用）
+func Ready() {
+	lastTickAt.Store(time.Now().UnixMilli())
+	go func() {
+		for {
+			<-timingWheel.Add(time.Second).C
+			lastTickAt.Store(time.Now().UnixMilli())
+		}
+	}()
+	isReady.Store(true)
+}
+
+// Closing 节点关闭中，不再接收流量
+func Closing() {
+	isReady.Store(false)
+	isClosing.Store(true)
+}
+
+// Healthz 进程存活，时间轮正常运转
+func Healthz() HealthDTO {
+	tickAt := time.UnixMilli(lastTickAt.Load())
+	if isReady.Load() && time.Since(tickAt) > tickTimeout {
+		exception.ThrowWebExceptionf(503, "时间轮已停止，最后触发时间：%s", tickAt.Format(time.DateTime))
+	}
+	return HealthDTO{TickAt: tickAt}
+}
+
+// Cluster 进程存活，及当前节点是否为Master（不返回集群节点等信息）
+func Cluster(scheduleRepository schedule.Repository) ClusterDTO {
+	health := Healthz()
+	leaderId := scheduleRepository.GetLeaderId()
+	return ClusterDTO{
+		TickAt:    health.TickAt,
+		IsLeader:  leaderId == fs.AppId,
+		HasLeader: leaderId > 0,
+	}
+}
+
+// Readyz 数据库、Redis可以访问，仓储已注册，启动流程已完成
+func Readyz() ReadyDTO {
+	if isClosing.Load() {
+		exception.ThrowWebException(503, "关闭中")
+	}
+	if !isReady.Load() {
+		exception.ThrowWebException(503, "启动中")
+	}
+
+	var checks, errs []string
+	for name, isRegister := range map[string]bool{
+		"taskGroup.Repository":  container.IsRegister[taskGroup.Repository](),
+		"client.Repository":     container.IsRegister[client.Repository](),
+		"schedule.Repository":   container.IsRegister[schedule.Repository](),
+		"serverNode.Repository": container.IsRegister[serverNode.Repository](),
+		"taskLog.Repository":    container.IsRegister[taskLog.Repository](),
+		"audit.Repository":      container.IsRegister[audit.Repository](),
+		"archive.Repository":    container.IsRegister[archive.Repository](),
+	} {
+		if !isRegister {
+			errs = append(errs, name+"：未注册")
+		}
+	}
+
+	// 数据库、Redis
+	for _, healthCheck := range container.ResolveAll[core.IHealthCheck]() {
+		item, err := healthCheck.Check()
+		if err != nil {
+			errs = append(errs, item+"："+err.Error())
+			continue
+		}
+		checks = append(checks, item)
+	}
+	if len(errs) > 0 {
+		exception.ThrowWebException(503, strings.Join(errs, "；"))
+	}
+	return ReadyDTO{Checks: checks}
+}
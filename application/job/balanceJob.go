@@ -0,0 +1,52 @@
+package job
+
+import (
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/tasks"
+	"time"
+)
+
+// BalanceJob 将任务组按一致性哈希分配给集群节点，节点加入、离开时重新分配，并通知各节点交出多分配的任务组
+func BalanceJob(context *tasks.TaskContext) {
+	var nodeIds []int64
+	for _, serverNodeDO := range container.Resolve[serverNode.Repository]().ToList().ToArray() {
+		if time.Since(serverNodeDO.ActivateAt).Seconds() < 30 {
+			nodeIds = append(nodeIds, serverNodeDO.Id)
+		}
+	}
+	if len(nodeIds) == 0 {
+		return
+	}
+
+	weights := make(map[string]int64)
+	for _, taskGroupDO := range container.Resolve[taskGroup.Repository]().ToList().ToArray() {
+		weights[taskGroupDO.Name] = taskGroupDO.Weight()
+	}
+
+	scheduleRepository := container.Resolve[schedule.Repository]()
+	assign := schedule.Balance(nodeIds, weights)
+	if isSameAssign(assign, scheduleRepository.GetAssign()) {
+		return
+	}
+	scheduleRepository.SaveAssign(assign)
+	flog.Infof("任务组重新分配：%s个节点，%s个任务组", flog.Red(len(nodeIds)), flog.Blue(len(assign)))
+	_ = container.Resolve[core.IEvent]("ScheduleAssign").Publish(fs.AppId)
+}
+
+func isSameAssign(assign map[string]int64, oldAssign map[string]int64) bool {
+	if len(assign) != len(oldAssign) {
+		return false
+	}
+	for name, nodeId := range assign {
+		if oldAssign[name] != nodeId {
+			return false
+		}
+	}
+	return true
+}
@@ -1,16 +1,24 @@
 package job
 
 import (
+	"FSchedule/domain/archive"
+	"FSchedule/domain/taskEvent"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
 	"github.com/farseer-go/fs/configure"
 	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/tasks"
 )
 
+// 每次归档的任务数量
+const archiveBatchSize = 500
+
 // ClearHisTaskJob 自动清除历史任务记录
 func ClearHisTaskJob(context *tasks.TaskContext) {
 	reservedTaskCount := configure.GetInt("FSchedule.ReservedTaskCount")
 	taskGroupRepository := container.Resolve[taskGroup.Repository]()
+	archiveRepository := container.Resolve[archive.Repository]()
 
 	curIndex := 0
 	result := 0
@@ -25,9 +33,46 @@ func ClearHisTaskJob(context *tasks.TaskContext) {
 		result += lstTask.Count()
 		var taskId = lstTask.Min(func(item taskGroup.TaskEO) any {
 			return item.Id
-		}).(int)
+		}).(int64)
+
+		// 先归档再清除
+		if archiveRepository.IsEnable() {
+			archiveTask(taskGroupDO.Name, taskId, taskGroupRepository, archiveRepository)
+			continue
+		}
 
 		// 清除历史记录
 		taskGroupRepository.ClearFinish(taskGroupDO.Name, taskId)
+		container.Resolve[taskEvent.Repository]().Clear(taskGroupDO.Name, taskId)
+	}
+}
+
+// archiveTask 分批归档任务及日志，归档成功后才删除
+func archiveTask(name string, taskId int64, taskGroupRepository taskGroup.Repository, archiveRepository archive.Repository) {
+	taskLogRepository := container.Resolve[taskLog.Repository]()
+	for {
+		lstTask := taskGroupRepository.ToClearList(name, taskId, archiveBatchSize)
+		if lstTask.Count() == 0 {
+			return
+		}
+		taskIds := make([]int64, 0, lstTask.Count())
+		for _, task := range lstTask.ToArray() {
+			taskIds = append(taskIds, task.Id)
+		}
+		lstLog := taskLogRepository.ToListByTaskIds(taskIds)
+
+		key, err := archiveRepository.Save(name, lstTask, lstLog)
+		if err != nil {
+			_ = flog.Errorf("归档任务组：%s 失败，本次不清除历史记录：%s", name, err.Error())
+			return
+		}
+		taskLogRepository.RemoveByTaskIds(taskIds)
+		container.Resolve[taskEvent.Repository]().RemoveByTaskIds(taskIds)
+		taskGroupRepository.RemoveTasks(taskIds)
+		flog.Infof("任务组：%s 已归档%d条任务、%d条日志：%s", name, lstTask.Count(), lstLog.Count(), key)
+
+		if lstTask.Count() < archiveBatchSize {
+			return
+		}
 	}
 }
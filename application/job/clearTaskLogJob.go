@@ -0,0 +1,74 @@
+package job
+
+import (
+	"FSchedule/domain/serverNode"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/tasks"
+	"time"
+)
+
+// 每批清除的日志数量
+const clearLogBatchSize = 1000
+
+// 每批之间的间隔，避免长时间占用数据库
+const clearLogBatchInterval = 100 * time.Millisecond
+
+// ClearTaskLogJob 按任务组的保留策略，分批清除过期的任务日志
+func ClearTaskLogJob(context *tasks.TaskContext) {
+	taskGroupRepository := container.Resolve[taskGroup.Repository]()
+	taskLogRepository := container.Resolve[taskLog.Repository]()
+
+	var result int64
+	defer func() {
+		if result > 0 {
+			flog.Infof("清除过期的任务日志：%d条", result)
+		}
+	}()
+
+	// 单独设置了保留策略的任务组
+	var names []string
+	for _, taskGroupDO := range taskGroupRepository.ToList().ToArray() {
+		if taskGroupDO.LogRetention.IsEmpty() {
+			continue
+		}
+		names = append(names, taskGroupDO.Name)
+		name := taskGroupDO.Name
+		if !clearTaskLog(taskGroupDO.LogRetention, &result, func(level eumLogLevel.Enum, before time.Time) int64 {
+			return taskLogRepository.Clear(name, level, before, clearLogBatchSize)
+		}) {
+			return
+		}
+	}
+
+	// 其它任务组（包括已删除的任务组）按全局配置清除
+	clearTaskLog(taskGroup.DefaultLogRetention(), &result, func(level eumLogLevel.Enum, before time.Time) int64 {
+		return taskLogRepository.ClearExcept(names, level, before, clearLogBatchSize)
+	})
+}
+
+// 按保留策略逐个级别分批清除，失去Master时返回false
+func clearTaskLog(retention taskGroup.LogRetentionVO, result *int64, clear func(level eumLogLevel.Enum, before time.Time) int64) bool {
+	for level := eumLogLevel.Trace; level < eumLogLevel.NoneLevel; level++ {
+		before := retention.ClearBefore(level)
+		if before.IsZero() {
+			continue
+		}
+		for {
+			// 失去Master后停止清除
+			if !serverNode.IsLeaderNode {
+				return false
+			}
+			count := clear(level, before)
+			*result += count
+			if count < clearLogBatchSize {
+				break
+			}
+			time.Sleep(clearLogBatchInterval)
+		}
+	}
+	return true
+}
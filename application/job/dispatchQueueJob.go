@@ -0,0 +1,11 @@
+package job
+
+import (
+	"FSchedule/domain"
+	"github.com/farseer-go/tasks"
+)
+
+// DispatchQueueJob 通知排队中的任务组重新调度
+func DispatchQueueJob(*tasks.TaskContext) {
+	domain.DispatchPending()
+}
@@ -1,6 +1,7 @@
 package job
 
 import (
+	"FSchedule/application/taskGroupApp"
 	"FSchedule/domain"
 	"FSchedule/domain/taskGroup"
 	"github.com/farseer-go/fs/container"
@@ -12,6 +13,7 @@ func InitTaskGroupMonitor() {
 	lst := repository.ToList()
 	for i := 0; i < lst.Count(); i++ {
 		taskGroupDO := lst.Index(i)
+		taskGroupApp.EnsureVersion(taskGroupDO, repository)
 		domain.MonitorTaskGroupPush(&taskGroupDO)
 	}
 }
@@ -13,6 +13,9 @@ import (
 // PrintInfoJob 打印客户端、任务组信息
 func PrintInfoJob(context *tasks.TaskContext) {
 	flog.Printf("%s个客户端（%s个正常），%s个任务组（%s个运行中）\n", flog.Red(domain.ClientCount()), flog.Green(domain.ClientNormalCount()), flog.Red(domain.TaskGroupCount()), flog.Green(domain.TaskGroupEnableCount()))
+	if pendingCount := domain.PendingCount(); pendingCount > 0 {
+		flog.Printf("%s个任务组排队等待调度，最长等待：%s ms\n", flog.Yellow(pendingCount), flog.Red(domain.PendingMaxWaitTime()))
+	}
 	lst := container.Resolve[serverNode.Repository]().ToList()
 
 	// 主节点
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"FSchedule/application/clientApp"
+	"FSchedule/domain/client"
 	"FSchedule/domain/serverNode"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/flog"
@@ -11,6 +13,7 @@ import (
 // ServerNodeTimeoutJob 移除30秒不活跃的
 func ServerNodeTimeoutJob(context *tasks.TaskContext) {
 	repository := container.Resolve[serverNode.Repository]()
+	clientRepository := container.Resolve[client.Repository]()
 	lst := repository.ToList()
 
 	for i := 0; i < lst.Count(); i++ {
@@ -18,6 +21,9 @@ func ServerNodeTimeoutJob(context *tasks.TaskContext) {
 		if time.Since(serverNodeDO.ActivateAt).Seconds() >= 30 {
 			repository.Remove(serverNodeDO.Id)
 			flog.Infof("集群节点：%s %s:%d 不再活跃，移出集群", flog.Green(serverNodeDO.Id), flog.Yellow(serverNodeDO.Ip), serverNodeDO.Port)
+
+			// 连接在该节点上的长连接客户端，如果没有重连到其它节点，则下线
+			clientApp.DisconnectByServer(serverNodeDO.Id, clientRepository)
 		}
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"FSchedule/application/job"
 	"FSchedule/domain"
 	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"context"
 	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
@@ -32,6 +34,9 @@ func (module Module) PostInitialize() {
 	// 10秒更新一次服务端信息
 	tasks.Run("ServerNodeJob", 10*time.Second, job.ServerActivateJob, fs.Context)
 
+	// 超过限流排队的任务组，按顺序重新调度
+	tasks.Run("DispatchQueueJob", 200*time.Millisecond, job.DispatchQueueJob, fs.Context)
+
 	fs.AddInitCallback("初始化任务组监听", func() {
 		job.InitTaskGroupMonitor()
 	})
@@ -42,7 +47,12 @@ func (module Module) PostInitialize() {
 
 	fs.AddInitCallback("选举", func() {
 		// 抢占锁，谁抢到，谁就是master
-		container.Resolve[schedule.Repository]().Election(func() {
+		container.Resolve[schedule.Repository]().Election(func(ctx context.Context) {
+			serverNode.LeaderContext = ctx
+			go func() {
+				<-ctx.Done()
+				serverNode.IsLeaderNode = false
+			}()
 			// 推送当前选举结果
 			_ = container.Resolve[core.IEvent]("ClusterLeader").Publish(fs.AppId)
 		})
@@ -0,0 +1,141 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/secret"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"time"
+)
+
+// 终止任务时，等待客户端回调释放锁的最长时间
+const killLockWait = 5 * time.Second
+
+type NameDTO struct {
+	Name string // 任务组名称
+}
+
+type TriggerDTO struct {
+	Name string            // 任务组名称
+	Data map[string]string // 只覆盖本次执行的参数
+}
+
+type InfoDTO struct {
+	TaskGroup taskGroup.DomainObject             // 任务组
+	Tasks     collections.List[taskGroup.TaskEO] // 最近完成的任务
+	Form      []taskGroup.FormFieldVO            // 参数的表单（按参数定义渲染）
+}
+
+// List 任务组列表
+func List(taskGroupRepository taskGroup.Repository) collections.List[taskGroup.DomainObject] {
+	lst := taskGroupRepository.ToList().OrderBy(func(item taskGroup.DomainObject) any {
+		return item.Name
+	}).ToArray()
+	for i := range lst {
+		lst[i] = lst[i].Masked()
+	}
+	return collections.NewList(lst...)
+}
+
+// Info 任务组详情
+func Info(name string, taskGroupRepository taskGroup.Repository) InfoDTO {
+	do := getTaskGroup(name, taskGroupRepository)
+	tasks := taskGroupRepository.ToFinishList(name, 10).ToArray()
+	for i := range tasks {
+		tasks[i] = tasks[i].Masked(do.DataSchema.SecretNames()...)
+	}
+	return InfoDTO{
+		TaskGroup: do.Masked(),
+		Tasks:     collections.NewList(tasks...),
+		Form:      do.DataSchema.Form(),
+	}
+}
+
+// Trigger 立即执行一次
+func Trigger(dto TriggerDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := getTaskGroup(dto.Name, taskGroupRepository)
+	if !do.IsEnable {
+		exception.ThrowWebExceptionf(403, "任务组：%s 已停止", dto.Name)
+	}
+	if len(dto.Data) > 0 {
+		if err := do.DataSchema.Validate(do.MergeData(dto.Data)); err != nil {
+			exception.ThrowWebExceptionf(403, "任务组：%s %s", dto.Name, err.Error())
+		}
+	}
+	beforeDO := do
+	if !do.Trigger(dto.Data) {
+		exception.ThrowWebExceptionf(403, "任务组：%s 正在执行中", dto.Name)
+	}
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "Trigger", actor, ip, do.Name, beforeDO, do))
+}
+
+// Kill 终止执行中的任务
+func Kill(dto NameDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, clientRepository client.Repository, scheduleRepository schedule.Repository, auditRepository audit.Repository) {
+	do := getTaskGroup(dto.Name, taskGroupRepository)
+	if !do.Task.IsWorking() {
+		exception.ThrowWebExceptionf(403, "任务组：%s 没有执行中的任务", dto.Name)
+	}
+
+	// 拉取模式的客户端无法主动通知，由客户端续约时发现任务已结束
+	if !do.Task.IsLeased() {
+		clientDO := clientRepository.ToEntity(do.Task.Client.Id)
+		if !clientDO.IsNil() && !clientDO.IsOffline() && !clientDO.ClientCheck().Kill(&clientDO, do.Task.Id) {
+			flog.Warningf("任务组：%s %d 通知客户端（%d）终止任务失败", do.Name, do.Task.Id, clientDO.Id)
+		}
+	}
+
+	// 与客户端回调使用同一把锁，避免覆盖客户端的上报，超时仍未获取到锁时返回409
+	lock := scheduleRepository.ScheduleLock(do.Name, do.Task.Id)
+	deadline := time.Now().Add(killLockWait)
+	for !lock.TryLockRun(func() { kill(dto.Name, do.Task.Id, actor, ip, taskGroupRepository, auditRepository) }) {
+		if time.Now().After(deadline) {
+			exception.ThrowWebExceptionf(409, "任务组：%s 正在处理客户端的上报，请稍后重试", dto.Name)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// 终止任务（已加锁），任务已结束或被其它任务取代时返回409
+func kill(name string, taskId int64, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := taskGroupRepository.ToEntity(name)
+	if do.Task.Id != taskId || !do.Task.IsWorking() {
+		exception.ThrowWebExceptionf(409, "任务组：%s %d 已结束执行", name, taskId)
+	}
+	beforeDO := do
+	if !do.Kill() {
+		exception.ThrowWebExceptionf(409, "任务组：%s %d 状态不允许从%s变更为%s", name, taskId, do.Task.Status.String(), enum.Fail.String())
+	}
+	events := do.PopEvents()
+	taskGroupRepository.Save(do)
+	PublishEvents(events)
+	auditRepository.Add(audit.New(enum.Admin, "Kill", actor, ip, do.Name, beforeDO, do))
+}
+
+func getTaskGroup(name string, taskGroupRepository taskGroup.Repository) taskGroup.DomainObject {
+	do := taskGroupRepository.ToEntity(name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", name)
+	}
+	return do
+}
+
+// LogList 任务组的日志（afterId大于0时，只返回该ID之后的日志，用于持续跟踪）
+func LogList(name string, taskId int64, afterId int64, top int, taskLogRepository taskLog.Repository, taskGroupRepository taskGroup.Repository) collections.List[taskLog.DomainObject] {
+	if top < 1 || top > 1000 {
+		top = 100
+	}
+	do := taskGroupRepository.ToEntity(name)
+	names := do.DataSchema.SecretNames()
+	lst := taskLogRepository.ToList(name, taskId, afterId, top).ToArray()
+	for i := range lst {
+		lst[i].Data = secret.MaskData(lst[i].Data, names...)
+	}
+	return collections.NewList(lst...)
+}
@@ -0,0 +1,72 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/tracing"
+	"github.com/farseer-go/fs/exception"
+	"unicode/utf8"
+)
+
+// 断点的最大长度
+const maxCheckpointSize = 64 * 1024
+
+// 进度消息的最大长度
+const maxMessageLength = 256
+
+type checkpointDTO struct {
+	TaskId      int64  // 主键
+	Name        string // 实现Job的特性名称（客户端识别哪个实现类）
+	Attempt     int    // 第几次下发（小于任务当前的下发次数时，拒绝保存）
+	Checkpoint  string // 断点（客户端自定义的续跑令牌，服务端不解析）
+	Progress    int    // 当前进度
+	Message     string // 进度消息
+	TraceParent string // 调度链路（W3C traceparent）
+}
+
+// Checkpoint 客户端执行中保存断点，故障转移后下一个客户端从断点续跑
+func Checkpoint(dto checkpointDTO, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository) {
+	span := tracing.Start(dto.TraceParent, "Checkpoint", append(tracing.Task(dto.Name, dto.TaskId), tracing.Int("fschedule.progress", dto.Progress))...)
+	defer span.End()
+	if len(dto.Checkpoint) > maxCheckpointSize {
+		exception.ThrowWebExceptionf(403, "断点长度不能超过%d字节", maxCheckpointSize)
+	}
+	if utf8.RuneCountInString(dto.Message) > maxMessageLength {
+		exception.ThrowWebExceptionf(403, "进度消息不能超过%d个字符", maxMessageLength)
+	}
+
+	scheduleRepository.ScheduleLock(dto.Name, dto.TaskId).GetLockRun(func() {
+		taskGroupDO := taskGroupRepository.ToEntity(dto.Name)
+		if taskGroupDO.IsNil() {
+			exception.ThrowWebExceptionf(403, "任务组[%s] 不存在", dto.Name)
+		}
+		// 并行执行中的任务，只保存任务（客户端下线时由RunningTaskOffline带到下一个任务）
+		if taskGroupDO.Task.Id != dto.TaskId && taskGroupDO.IsRunningTask(dto.TaskId) {
+			taskEO := taskGroupRepository.GetTask(dto.Name, dto.TaskId)
+			checkWorking(taskEO, dto)
+			taskEO.SetCheckpoint(dto.Checkpoint, dto.Progress, dto.Message)
+			taskGroupRepository.SaveTask(taskEO)
+			return
+		}
+		if taskGroupDO.Task.Id != dto.TaskId {
+			exception.ThrowWebExceptionf(409, "任务id={%d} 不在执行中", dto.TaskId)
+		}
+		checkWorking(taskGroupDO.Task, dto)
+
+		// 拉取模式的任务，保存断点时续约
+		taskGroupDO.RenewLease(getLeaseTime())
+		taskGroupDO.Task.SetCheckpoint(dto.Checkpoint, dto.Progress, dto.Message)
+		taskGroupRepository.Save(taskGroupDO)
+	})
+}
+
+// 不在执行中、或已被新的下发取代的断点，拒绝
+func checkWorking(taskEO taskGroup.TaskEO, dto checkpointDTO) {
+	if taskEO.IsNull() || taskEO.Status != enum.Working {
+		exception.ThrowWebExceptionf(409, "任务id={%d} 不在执行中", dto.TaskId)
+	}
+	if taskEO.IsSuperseded(dto.Attempt) {
+		exception.ThrowWebExceptionf(409, "任务id={%d} 第%d次下发已被第%d次取代", dto.TaskId, dto.Attempt, taskEO.Attempt)
+	}
+}
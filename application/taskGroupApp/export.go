@@ -0,0 +1,192 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/secret"
+	"FSchedule/domain/taskGroup"
+	"encoding/json"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"github.com/farseer-go/fs/exception"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigDocument 任务组的声明式配置
+type ConfigDocument struct {
+	TaskGroups []TaskGroupConfigDTO `yaml:"TaskGroups"`
+}
+
+// TaskGroupConfigDTO 任务组的定义（不包含RunCount、RunSpeedAvg等运行数据）
+type TaskGroupConfigDTO struct {
+	Name              string                 `yaml:"Name"`              // 任务组名称
+	Ver               int                    `yaml:"Ver"`               // 版本（仅新建任务组时使用，之后由客户端注册更新）
+	Caption           string                 `yaml:"Caption"`           // 任务组标题
+	Cron              string                 `yaml:"Cron"`              // 时间定时器表达式
+	StartAt           time.Time              `yaml:"StartAt"`           // 开始时间
+	IsEnable          bool                   `yaml:"IsEnable"`          // 是否开启
+	Data              map[string]string      `yaml:"Data,omitempty"`    // 传给客户端的参数
+	ConcurrencyPolicy enum.ConcurrencyPolicy `yaml:"ConcurrencyPolicy"` // 任务执行中到达下一个周期时的处理策略
+	MaxParallel       int                    `yaml:"MaxParallel"`       // 最多同时执行的任务数量
+	Priority          int                    `yaml:"Priority"`          // 优先级
+	Namespace         string                 `yaml:"Namespace"`         // 命名空间
+	VersionPolicy     enum.VersionPolicy     `yaml:"VersionPolicy"`     // 调度客户端版本的策略
+	AllowPrevious     int                    `yaml:"AllowPrevious"`     // 允许调度给前N个版本的客户端
+	Canary            CanaryConfigDTO        `yaml:"Canary"`            // 金丝雀发布
+	LogRetention      LogRetentionConfigDTO  `yaml:"LogRetention"`      // 任务日志的保留策略
+}
+
+// LogRetentionConfigDTO 任务日志保留策略的配置
+type LogRetentionConfigDTO struct {
+	Days          int              `yaml:"Days"`          // 日志保留天数（Days、KeepLevelDays都为0时，使用全局配置）
+	KeepLevel     eumLogLevel.Enum `yaml:"KeepLevel"`     // 达到该级别的日志，按KeepLevelDays保留
+	KeepLevelDays int              `yaml:"KeepLevelDays"` // 达到KeepLevel的日志保留天数
+}
+
+// CanaryConfigDTO 金丝雀发布的配置
+type CanaryConfigDTO struct {
+	IsEnable      bool     `yaml:"IsEnable"`       // 客户端注册新版本时，是否进入金丝雀发布
+	Percent       int      `yaml:"Percent"`        // 调度给新版本的百分比
+	Tags          []string `yaml:"Tags,omitempty"` // 只调度给带有这些标签的新版本客户端
+	PromoteCount  int      `yaml:"PromoteCount"`   // 连续成功多少次后，自动全量发布
+	RollbackCount int      `yaml:"RollbackCount"`  // 连续失败多少次后，自动回滚
+}
+
+// Export 导出所有任务组的定义（format：yaml、json）
+func Export(format string, taskGroupRepository taskGroup.Repository) string {
+	var doc ConfigDocument
+	for _, do := range taskGroupRepository.ToList().ToArray() {
+		doc.TaskGroups = append(doc.TaskGroups, toConfig(do))
+	}
+	sort.Slice(doc.TaskGroups, func(i, j int) bool {
+		return doc.TaskGroups[i].Name < doc.TaskGroups[j].Name
+	})
+	return marshalConfig(doc, format)
+}
+
+// 任务组转成配置
+func toConfig(do taskGroup.DomainObject) TaskGroupConfigDTO {
+	cfg := TaskGroupConfigDTO{
+		Name:              do.Name,
+		Ver:               do.Ver,
+		Caption:           do.Caption,
+		Cron:              do.Cron,
+		StartAt:           do.StartAt,
+		IsEnable:          do.IsEnable,
+		ConcurrencyPolicy: do.ConcurrencyPolicy,
+		MaxParallel:       do.MaxParallel,
+		Priority:          do.Priority,
+		Namespace:         do.Namespace,
+		VersionPolicy:     do.VersionPolicy,
+		AllowPrevious:     do.AllowPrevious,
+		Canary: CanaryConfigDTO{
+			IsEnable:      do.Canary.IsEnable,
+			Percent:       do.Canary.Percent,
+			Tags:          do.Canary.Tags,
+			PromoteCount:  do.Canary.PromoteCount,
+			RollbackCount: do.Canary.RollbackCount,
+		},
+		LogRetention: LogRetentionConfigDTO{
+			Days:          do.LogRetention.Days,
+			KeepLevel:     do.LogRetention.KeepLevel,
+			KeepLevelDays: do.LogRetention.KeepLevelDays,
+		},
+	}
+	// Secret参数脱敏，导入时保持"******"表示不修改
+	if data := secret.MaskData(do.Data, do.DataSchema.SecretNames()...); data.Count() > 0 {
+		cfg.Data = make(map[string]string)
+		for k, v := range data.ToMap() {
+			cfg.Data[k] = v
+		}
+	}
+	cfg.normalize()
+	return cfg
+}
+
+// 导入的配置中修改了的Secret参数，比较差异时脱敏
+func maskConfig(cfg TaskGroupConfigDTO, secretNames []string) TaskGroupConfigDTO {
+	if len(secretNames) == 0 || len(cfg.Data) == 0 {
+		return cfg
+	}
+	data := make(map[string]string, len(cfg.Data))
+	for k, v := range cfg.Data {
+		data[k] = v
+	}
+	for _, name := range secretNames {
+		if value := data[name]; value != "" && value != secret.Mask {
+			data[name] = secret.Mask + "（已修改）"
+		}
+	}
+	cfg.Data = data
+	return cfg
+}
+
+// 空集合统一为nil，避免比较时产生差异
+func (receiver *TaskGroupConfigDTO) normalize() {
+	if len(receiver.Data) == 0 {
+		receiver.Data = nil
+	}
+	if len(receiver.Canary.Tags) == 0 {
+		receiver.Canary.Tags = nil
+	}
+}
+
+// 配置更新到任务组
+func applyConfig(do *taskGroup.DomainObject, cfg TaskGroupConfigDTO) {
+	do.IsImported = true
+	do.ApplyDefinition(taskGroup.VersionEO{
+		Name:              cfg.Name,
+		Ver:               do.Ver,
+		Caption:           cfg.Caption,
+		Cron:              cfg.Cron,
+		StartAt:           cfg.StartAt,
+		IsEnable:          cfg.IsEnable,
+		Data:              collections.NewDictionaryFromMap(cfg.Data),
+		ConcurrencyPolicy: cfg.ConcurrencyPolicy,
+		MaxParallel:       cfg.MaxParallel,
+		Priority:          cfg.Priority,
+		Namespace:         cfg.Namespace,
+	})
+	do.SetVersionPolicy(cfg.VersionPolicy, cfg.AllowPrevious)
+	do.SetCanary(taskGroup.CanaryVO{
+		IsEnable:      cfg.Canary.IsEnable,
+		Percent:       cfg.Canary.Percent,
+		Tags:          cfg.Canary.Tags,
+		PromoteCount:  cfg.Canary.PromoteCount,
+		RollbackCount: cfg.Canary.RollbackCount,
+	}, false)
+	do.SetLogRetention(taskGroup.LogRetentionVO{
+		Days:          cfg.LogRetention.Days,
+		KeepLevel:     cfg.LogRetention.KeepLevel,
+		KeepLevelDays: cfg.LogRetention.KeepLevelDays,
+	})
+}
+
+func marshalConfig(doc ConfigDocument, format string) string {
+	if isJson(format) {
+		marshal, _ := json.MarshalIndent(doc, "", "  ")
+		return string(marshal)
+	}
+	marshal, _ := yaml.Marshal(doc)
+	return string(marshal)
+}
+
+func unmarshalConfig(content string, format string) ConfigDocument {
+	var doc ConfigDocument
+	var err error
+	if isJson(format) {
+		err = json.Unmarshal([]byte(content), &doc)
+	} else {
+		err = yaml.Unmarshal([]byte(content), &doc)
+	}
+	if err != nil {
+		exception.ThrowWebExceptionf(403, "配置格式错误：%s", err.Error())
+	}
+	return doc
+}
+
+func isJson(format string) bool {
+	return strings.ToLower(format) == "json"
+}
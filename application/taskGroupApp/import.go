@@ -0,0 +1,113 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+type ImportDTO struct {
+	Format  string // 配置格式：yaml、json
+	Content string // 配置内容
+	DryRun  bool   // 只返回差异，不修改
+	Prune   bool   // 配置中不存在的任务组，设为停止状态
+}
+
+type ImportChangeDTO struct {
+	Name   string         // 任务组名称
+	Action string         // Create、Update、Disable
+	Diff   []audit.DiffVO // 变更的字段
+}
+
+// Import 导入任务组的定义，使集群状态与配置一致
+func Import(dto ImportDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) []ImportChangeDTO {
+	doc := unmarshalConfig(dto.Content, dto.Format)
+	names := make(map[string]bool)
+	for _, cfg := range doc.TaskGroups {
+		if cfg.Name == "" {
+			exception.ThrowWebException(403, "任务组名称不能为空")
+		}
+		if names[cfg.Name] {
+			exception.ThrowWebExceptionf(403, "任务组：%s 重复定义", cfg.Name)
+		}
+		if !taskGroup.CheckCron(cfg.Cron) {
+			exception.ThrowWebExceptionf(403, "任务组：%s Cron格式错误：%s", cfg.Name, cfg.Cron)
+		}
+		// 已注册参数定义的任务组，校验参数
+		if do := taskGroupRepository.ToEntity(cfg.Name); !do.IsNil() {
+			if err := do.ValidateData(collections.NewDictionaryFromMap(cfg.Data)); err != nil {
+				exception.ThrowWebExceptionf(403, "任务组：%s %s", cfg.Name, err.Error())
+			}
+		}
+		names[cfg.Name] = true
+	}
+
+	var changes []ImportChangeDTO
+	for _, cfg := range doc.TaskGroups {
+		cfg.normalize()
+		do := taskGroupRepository.ToEntity(cfg.Name)
+		change := ImportChangeDTO{Name: cfg.Name, Action: "Update"}
+		if do.IsNil() {
+			change.Action = "Create"
+			do = taskGroup.DomainObject{Name: cfg.Name, Ver: cfg.Ver}
+			change.Diff = audit.DiffObject(nil, cfg)
+		} else {
+			// 版本由客户端注册时更新，导入时保持不变
+			cfg.Ver = do.Ver
+			if cfg.StartAt.Equal(do.StartAt) {
+				cfg.StartAt = do.StartAt
+			}
+			change.Diff = audit.DiffObject(toConfig(do), maskConfig(cfg, do.DataSchema.SecretNames()))
+		}
+		if len(change.Diff) == 0 {
+			continue
+		}
+		changes = append(changes, change)
+
+		if !dto.DryRun {
+			beforeDO := do
+			applyConfig(&do, cfg)
+			// 新建的任务组立即写入数据库，不依赖缓存同步
+			if change.Action == "Create" {
+				taskGroupRepository.Add(&do)
+			} else {
+				taskGroupRepository.Save(do)
+			}
+			auditRepository.Add(audit.New(enum.Admin, "Import"+change.Action, actor, ip, do.Name, beforeDO, do))
+		}
+	}
+
+	// 配置中不存在的任务组，设为停止状态
+	if dto.Prune {
+		for _, do := range taskGroupRepository.ToList().ToArray() {
+			if names[do.Name] || !do.IsEnable {
+				continue
+			}
+			changes = append(changes, ImportChangeDTO{Name: do.Name, Action: "Disable", Diff: []audit.DiffVO{{Field: "IsEnable", Before: "true", After: "false"}}})
+			if !dto.DryRun {
+				beforeDO := do
+				do.SetEnable(false)
+				taskGroupRepository.Save(do)
+				auditRepository.Add(audit.New(enum.Admin, "ImportDisable", actor, ip, do.Name, beforeDO, do))
+			}
+		}
+	}
+	return changes
+}
+
+// ReleaseImport 解除任务组的导入管理，之后由客户端注册时更新定义
+func ReleaseImport(name string, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := taskGroupRepository.ToEntity(name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", name)
+	}
+	if !do.IsImported {
+		return
+	}
+	beforeDO := do
+	do.ReleaseImport()
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "ReleaseImport", actor, ip, do.Name, beforeDO, do))
+}
@@ -2,14 +2,19 @@ package taskGroupApp
 
 import (
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLive"
 	"FSchedule/domain/taskLog"
+	"FSchedule/domain/tracing"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
 	"github.com/farseer-go/fs/core/eumLogLevel"
 )
 
 type logReportDTO struct {
-	TaskId int64  // 主键
-	Name   string // 实现Job的特性名称（客户端识别哪个实现类）
-	Log    []LogContent
+	TaskId      int64  // 主键
+	Name        string // 实现Job的特性名称（客户端识别哪个实现类）
+	Log         []LogContent
+	TraceParent string // 调度链路（W3C traceparent）
 }
 
 type LogContent struct {
@@ -20,9 +25,18 @@ type LogContent struct {
 
 // LogReport 日志上报
 func LogReport(dto logReportDTO, taskGroupRepository taskGroup.Repository, taskLogRepository taskLog.Repository) {
+	span := tracing.Start(dto.TraceParent, "LogReport", append(tracing.Task(dto.Name, dto.TaskId), tracing.Int("fschedule.log_count", len(dto.Log)))...)
+	defer span.End()
 	taskDO := taskGroupRepository.GetTask(dto.Name, dto.TaskId)
+	events := make([]taskLive.EventVO, 0, len(dto.Log))
 	for _, log := range dto.Log {
 		taskLogDO := taskLog.NewDO(dto.Name, taskDO.Caption, taskDO.Ver, taskDO.Id, taskDO.Data, log.LogLevel, log.Content, log.CreateAt)
 		taskLogRepository.Add(taskLogDO)
+		events = append(events, taskLive.NewLog(taskLogDO))
+	}
+
+	// 客户端可能上报到任意节点，集群中有订阅者时，通过Redis推送给所有节点的订阅者
+	if len(events) > 0 && container.Resolve[taskLive.Repository]().HasSubscriber(dto.Name) {
+		_ = container.Resolve[core.IEvent]("TaskLive").Publish(events)
 	}
 }
@@ -0,0 +1,110 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain"
+	"FSchedule/domain/client"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/mapper"
+	"time"
+)
+
+type PullDTO struct {
+	ClientId int64 // 客户端ID
+	Count    int   // 最多拉取的任务数量
+	Timeout  int   // 没有任务时，最长等待的秒数
+}
+
+// Pull 客户端拉取到期的任务（长轮询）
+func Pull(dto PullDTO, clientRepository client.Repository, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository) []client.TaskEO {
+	clientDO := clientRepository.ToEntity(dto.ClientId)
+	if clientDO.IsNil() || clientDO.IsOffline() {
+		exception.ThrowWebExceptionf(403, "客户端（%d）未注册", dto.ClientId)
+	}
+	if !clientDO.IsPull() {
+		exception.ThrowWebExceptionf(403, "客户端（%d）不是拉取模式", dto.ClientId)
+	}
+
+	// 拉取同时作为心跳
+	if clientDO.Pull() {
+		clientRepository.Save(&clientDO)
+	}
+
+	if dto.Count < 1 {
+		dto.Count = 1
+	}
+	if maxTimeout := int(client.MaxPullTimeout / time.Second); dto.Timeout < 0 || dto.Timeout > maxTimeout {
+		dto.Timeout = maxTimeout
+	}
+
+	var jobNames []string
+	jobVers := make(map[string]int)
+	for _, jobVO := range clientDO.Jobs.ToArray() {
+		jobNames = append(jobNames, jobVO.Name)
+		jobVers[jobVO.Name] = jobVO.Ver
+	}
+
+	leaseTime := getLeaseTime()
+	clientVO := mapper.Single[taskGroup.ClientVO](clientDO)
+	deadline := time.Now().Add(time.Duration(dto.Timeout) * time.Second)
+	var tasks []client.TaskEO
+	for {
+		// 先取通知，再查询，避免查询后、等待前的更新被漏掉
+		notice := domain.PullNotice()
+		// 每个任务组只有一个任务，所以取客户端支持的全部任务组
+		lst := taskGroupRepository.GetTaskUnFinishList(jobNames, len(jobNames))
+		for _, item := range lst.ToArray() {
+			if !item.CanPull() || !item.AllowVer(jobVers[item.Name]) || !item.IsCanaryClient(jobVers[item.Name], clientDO.Tags) || len(tasks) >= dto.Count {
+				continue
+			}
+
+			// 加锁，防止同一个任务被多个客户端拉取
+			scheduleRepository.ScheduleLock(item.Name, item.Task.Id).TryLockRun(func() {
+				taskGroupDO := taskGroupRepository.ToEntity(item.Name)
+				if taskGroupDO.Task.Id != item.Task.Id || !taskGroupDO.CanPull() {
+					return
+				}
+				// Secret参数只在下发给客户端时解密
+				data, err := taskGroupDO.Task.RevealData()
+				if err != nil {
+					_ = flog.Errorf("任务组：%s %d 解密Secret参数失败：%s", taskGroupDO.Name, taskGroupDO.Task.Id, err.Error())
+					return
+				}
+				if !taskGroupDO.Lease(clientVO, leaseTime) {
+					return
+				}
+				taskGroupDO.SetClientVer(jobVers[item.Name])
+				events := taskGroupDO.PopEvents()
+				taskGroupRepository.SaveAndTask(taskGroupDO)
+				PublishEvents(events)
+				clientTask := mapper.Single[client.TaskEO](taskGroupDO.Task)
+				clientTask.Data = data
+				clientTask.IdempotencyKey = taskGroupDO.Task.IdempotencyKey()
+				tasks = append(tasks, clientTask)
+				flog.Infof("任务组：%s 客户端（%d）拉取任务 %d", taskGroupDO.Name, clientDO.Id, taskGroupDO.Task.Id)
+			})
+		}
+
+		wait := time.Until(deadline)
+		if len(tasks) > 0 || wait <= 0 {
+			return tasks
+		}
+		// 等待有任务可拉取，或超时
+		select {
+		case <-notice:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// 拉取任务的租约时长，客户端需在租约到期前通过/api/taskReport续约
+func getLeaseTime() time.Duration {
+	leaseTime := configure.GetInt("FSchedule.PullLeaseTime")
+	if leaseTime <= 0 {
+		leaseTime = 60
+	}
+	return time.Duration(leaseTime) * time.Second
+}
@@ -0,0 +1,39 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/exception"
+)
+
+type SetCanaryDTO struct {
+	Name          string   // 任务组名称
+	IsEnable      bool     // 客户端注册新版本时，是否进入金丝雀发布
+	Start         bool     // 当前版本立即进入金丝雀发布
+	Percent       int      // 调度给新版本的百分比（未设置Tags时，Canary策略也按此百分比调度当前版本）
+	Tags          []string // 只调度给带有这些标签的新版本客户端
+	PromoteCount  int      // 连续成功多少次后，自动全量发布（0：不自动）
+	RollbackCount int      // 连续失败多少次后，自动回滚到上一个版本（0：不自动）
+}
+
+// SetCanary 设置金丝雀发布
+func SetCanary(dto SetCanaryDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	if dto.Percent < 0 || dto.Percent > 100 || dto.PromoteCount < 0 || dto.RollbackCount < 0 {
+		exception.ThrowWebException(403, "Percent需在0-100之间，PromoteCount、RollbackCount不能小于0")
+	}
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	beforeDO := do
+	do.SetCanary(taskGroup.CanaryVO{
+		IsEnable:      dto.IsEnable,
+		Percent:       dto.Percent,
+		Tags:          dto.Tags,
+		PromoteCount:  dto.PromoteCount,
+		RollbackCount: dto.RollbackCount,
+	}, dto.Start)
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "SetCanary", actor, ip, do.Name, beforeDO, do))
+}
@@ -0,0 +1,28 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+type SetDataDTO struct {
+	Name string            // 任务组名称
+	Data map[string]string // 传给客户端的参数
+}
+
+// SetData 修改传给客户端的参数（按客户端注册的参数定义校验）
+func SetData(dto SetDataDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	beforeDO := do
+	if err := do.SetData(collections.NewDictionaryFromMap(dto.Data)); err != nil {
+		exception.ThrowWebExceptionf(403, "任务组：%s %s", dto.Name, err.Error())
+	}
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "SetData", actor, ip, do.Name, beforeDO, do))
+}
@@ -0,0 +1,25 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/exception"
+)
+
+type SetEnableDTO struct {
+	Name     string // 任务组名称
+	IsEnable bool   // 是否开启
+}
+
+// SetEnable 开启、停止任务组
+func SetEnable(dto SetEnableDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	beforeDO := do
+	do.SetEnable(dto.IsEnable)
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "SetEnable", actor, ip, do.Name, beforeDO, do))
+}
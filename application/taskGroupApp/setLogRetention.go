@@ -0,0 +1,35 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"github.com/farseer-go/fs/exception"
+)
+
+type SetLogRetentionDTO struct {
+	Name          string           // 任务组名称
+	Days          int              // 日志保留天数（Days、KeepLevelDays都为0时，使用全局配置）
+	KeepLevel     eumLogLevel.Enum // 达到该级别的日志，按KeepLevelDays保留
+	KeepLevelDays int              // 达到KeepLevel的日志保留天数（0：与Days相同）
+}
+
+// SetLogRetention 设置任务日志的保留策略
+func SetLogRetention(dto SetLogRetentionDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	if dto.Days < 0 || dto.KeepLevelDays < 0 {
+		exception.ThrowWebException(403, "Days、KeepLevelDays不能小于0")
+	}
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	beforeDO := do
+	do.SetLogRetention(taskGroup.LogRetentionVO{
+		Days:          dto.Days,
+		KeepLevel:     dto.KeepLevel,
+		KeepLevelDays: dto.KeepLevelDays,
+	})
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "SetLogRetention", actor, ip, do.Name, beforeDO, do))
+}
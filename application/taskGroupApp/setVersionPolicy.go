@@ -0,0 +1,29 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/fs/exception"
+)
+
+type SetVersionPolicyDTO struct {
+	Name          string             // 任务组名称
+	VersionPolicy enum.VersionPolicy // 调度客户端版本的策略
+	AllowPrevious int                // 允许调度给前N个版本的客户端（AllowPrevious策略）
+}
+
+// SetVersionPolicy 设置调度客户端版本的策略
+func SetVersionPolicy(dto SetVersionPolicyDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	if dto.AllowPrevious < 0 {
+		exception.ThrowWebException(403, "AllowPrevious不能小于0")
+	}
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	beforeDO := do
+	do.SetVersionPolicy(dto.VersionPolicy, dto.AllowPrevious)
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "SetVersionPolicy", actor, ip, do.Name, beforeDO, do))
+}
@@ -0,0 +1,26 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/taskEvent"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+type TaskDTO struct {
+	Task   taskGroup.TaskEO                         // 任务
+	Events collections.List[taskEvent.DomainObject] // 状态变更历史（按时间顺序）
+}
+
+// Task 任务详情及状态变更历史
+func Task(name string, taskId int64, taskGroupRepository taskGroup.Repository, taskEventRepository taskEvent.Repository) TaskDTO {
+	taskEO := taskGroupRepository.GetTask(name, taskId)
+	if taskEO.IsNull() {
+		exception.ThrowWebExceptionf(403, "任务id={%d} 不存在", taskId)
+	}
+	do := taskGroupRepository.ToEntity(name)
+	return TaskDTO{
+		Task:   taskEO.Masked(do.DataSchema.SecretNames()...),
+		Events: taskEventRepository.ToList(taskId),
+	}
+}
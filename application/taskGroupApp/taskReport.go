@@ -4,6 +4,7 @@ import (
 	"FSchedule/domain/client"
 	"FSchedule/domain/schedule"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/tracing"
 	"github.com/farseer-go/fs/exception"
 	"github.com/farseer-go/fs/flog"
 )
@@ -11,6 +12,8 @@ import (
 // TaskReport 客户端回调
 func TaskReport(dto client.TaskReportVO, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository) {
 	flog.Debugf("任务组：%s %d 通知执行结果：%s", dto.Name, dto.Id, flog.Red(dto.Status.String()))
+	span := tracing.Start(dto.TraceParent, "TaskReport", append(tracing.Task(dto.Name, dto.Id), tracing.String("fschedule.status", dto.Status.String()), tracing.Int("fschedule.progress", dto.Progress))...)
+	defer span.End()
 	// 加锁
 	scheduleRepository.ScheduleLock(dto.Name, dto.Id).GetLockRun(func() {
 		taskGroupDO := taskGroupRepository.ToEntity(dto.Name)
@@ -23,12 +26,44 @@ func TaskReport(dto client.TaskReportVO, taskGroupRepository taskGroup.Repositor
 			if taskEO.IsNull() {
 				exception.ThrowWebExceptionf(403, "任务id={%d} 不存在", dto.Id)
 			}
+			checkAttempt(taskEO, dto)
 			// 更新任务
-			taskEO.UpdateTask(dto.Status, dto.Data, dto.Progress, dto.RunSpeed)
+			if !taskEO.UpdateTask(dto.Status, dto.Data, dto.Progress, dto.RunSpeed) {
+				throwTransit(taskEO, dto)
+			}
+			// 客户端上报的参数中包含明文的Secret参数
+			taskEO.Data = taskGroupDO.ProtectData(taskEO.Data)
+			events := taskEO.PopEvents()
 			taskGroupRepository.SaveTask(taskEO)
+			PublishEvents(events)
+
+			// 并行执行的任务完成
+			if taskGroupDO.RunningTaskFinish(taskEO) {
+				taskGroupRepository.Save(taskGroupDO)
+			}
 			return
 		}
 
-		taskGroupDO.Report(dto.Status, dto.Data, dto.Progress, dto.RunSpeed, dto.NextTimespan, taskGroupRepository)
+		checkAttempt(taskGroupDO.Task, dto)
+		// 拉取模式的任务，上报时续约
+		taskGroupDO.RenewLease(getLeaseTime())
+		if !taskGroupDO.Report(dto.Status, dto.Data, dto.Progress, dto.RunSpeed, dto.NextTimespan) {
+			throwTransit(taskGroupDO.Task, dto)
+		}
+		events := taskGroupDO.PopEvents()
+		taskGroupRepository.Save(taskGroupDO)
+		PublishEvents(events)
 	})
 }
+
+// 已被新的下发取代（重试、故障转移、租约回收）的上报，拒绝
+func checkAttempt(taskEO taskGroup.TaskEO, dto client.TaskReportVO) {
+	if taskEO.IsSuperseded(dto.Attempt) {
+		exception.ThrowWebExceptionf(409, "任务id={%d} 第%d次下发已被第%d次取代", dto.Id, dto.Attempt, taskEO.Attempt)
+	}
+}
+
+// 不允许的状态变更（如已完成的任务收到延迟的执行中上报），拒绝
+func throwTransit(taskEO taskGroup.TaskEO, dto client.TaskReportVO) {
+	exception.ThrowWebExceptionf(409, "任务id={%d} 状态不允许从%s变更为%s", dto.Id, taskEO.Status.String(), dto.Status.String())
+}
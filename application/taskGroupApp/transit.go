@@ -0,0 +1,18 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/taskEvent"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+)
+
+// PublishEvents 保存成功后，发布任务的状态变更事件（记录到状态变更历史）
+func PublishEvents(events []taskEvent.DomainObject) {
+	if len(events) == 0 || !container.IsRegister[core.IEvent]("TaskTransit") {
+		return
+	}
+	eventBus := container.Resolve[core.IEvent]("TaskTransit")
+	for _, event := range events {
+		_ = eventBus.Publish(event)
+	}
+}
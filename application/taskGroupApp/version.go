@@ -0,0 +1,75 @@
+package taskGroupApp
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/exception"
+)
+
+type VersionDiffDTO struct {
+	Name    string // 任务组名称
+	FromVer int    // 比较的版本
+	ToVer   int    // 比较的版本（0：当前定义）
+}
+
+type RollbackDTO struct {
+	Name string // 任务组名称
+	Ver  int    // 回滚到的版本
+}
+
+// EnsureVersion 升级前创建的任务组没有历史定义，首次加载或更新时保存当前定义
+func EnsureVersion(do taskGroup.DomainObject, taskGroupRepository taskGroup.Repository) {
+	if do.IsNil() || do.Ver <= 0 {
+		return
+	}
+	if version := taskGroupRepository.GetVersion(do.Name, do.Ver); version.IsNil() {
+		taskGroupRepository.AddVersion(do.ToVersion())
+	}
+}
+
+// VersionList 任务组的历史定义
+func VersionList(name string, taskGroupRepository taskGroup.Repository) collections.List[taskGroup.VersionEO] {
+	lst := taskGroupRepository.ToVersionList(name).ToArray()
+	for i := range lst {
+		lst[i] = lst[i].Masked()
+	}
+	return collections.NewList(lst...)
+}
+
+// VersionDiff 比较两个版本的定义
+func VersionDiff(dto VersionDiffDTO, taskGroupRepository taskGroup.Repository) []audit.DiffVO {
+	from := getVersion(dto.Name, dto.FromVer, taskGroupRepository)
+	var to taskGroup.VersionEO
+	if dto.ToVer == 0 {
+		do := taskGroupRepository.ToEntity(dto.Name)
+		to = do.ToVersion()
+		to.CreateAt = from.CreateAt
+	} else {
+		to = getVersion(dto.Name, dto.ToVer, taskGroupRepository)
+	}
+	return audit.DiffObject(from, to)
+}
+
+// Rollback 回滚到历史版本的定义
+func Rollback(dto RollbackDTO, actor string, ip string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	do := taskGroupRepository.ToEntity(dto.Name)
+	if do.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 不存在", dto.Name)
+	}
+	version := getVersion(dto.Name, dto.Ver, taskGroupRepository)
+
+	beforeDO := do
+	do.Rollback(version)
+	taskGroupRepository.Save(do)
+	auditRepository.Add(audit.New(enum.Admin, "Rollback", actor, ip, do.Name, beforeDO, do))
+}
+
+func getVersion(name string, ver int, taskGroupRepository taskGroup.Repository) taskGroup.VersionEO {
+	version := taskGroupRepository.GetVersion(name, ver)
+	if version.IsNil() {
+		exception.ThrowWebExceptionf(404, "任务组：%s 版本：%d 不存在", name, ver)
+	}
+	return version
+}
@@ -0,0 +1,58 @@
+package taskLogApp
+
+import (
+	"FSchedule/domain/secret"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
+type SearchDTO struct {
+	Name      string            // 任务组名称
+	TaskId    int64             // 任务ID
+	LogLevel  *eumLogLevel.Enum // 日志级别（大于等于）
+	Keyword   string            // 日志内容的关键字
+	StartAt   int64             // 开始时间（毫秒时间戳）
+	EndAt     int64             // 结束时间（毫秒时间戳）
+	PageSize  int               // 每页数量
+	PageIndex int               // 页码
+}
+
+// Search 搜索任务日志
+func Search(dto SearchDTO, taskLogRepository taskLog.Repository, taskGroupRepository taskGroup.Repository) collections.PageList[taskLog.DomainObject] {
+	filter := taskLog.FilterVO{
+		Name:     dto.Name,
+		TaskId:   dto.TaskId,
+		LogLevel: dto.LogLevel,
+		Keyword:  dto.Keyword,
+	}
+	if dto.StartAt > 0 {
+		filter.StartAt = time.UnixMilli(dto.StartAt)
+	}
+	if dto.EndAt > 0 {
+		filter.EndAt = time.UnixMilli(dto.EndAt)
+	}
+	if dto.PageSize < 1 {
+		dto.PageSize = 20
+	}
+	if dto.PageIndex < 1 {
+		dto.PageIndex = 1
+	}
+	pageList := taskLogRepository.Search(filter, dto.PageSize, dto.PageIndex)
+	lst := pageList.List.ToArray()
+	// 按各任务组的参数定义脱敏
+	secretNames := make(map[string][]string)
+	for i := range lst {
+		names, exists := secretNames[lst[i].Name]
+		if !exists {
+			taskGroupDO := taskGroupRepository.ToEntity(lst[i].Name)
+			names = taskGroupDO.DataSchema.SecretNames()
+			secretNames[lst[i].Name] = names
+		}
+		lst[i].Data = secret.MaskData(lst[i].Data, names...)
+	}
+	pageList.List = collections.NewList(lst...)
+	return pageList
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiClient 调用服务端的管理接口
+type apiClient struct {
+	server string
+	token  string
+	client *http.Client
+}
+
+// apiResponse 服务端返回的统一格式
+type apiResponse struct {
+	Status        bool
+	StatusCode    int
+	StatusMessage string
+	Data          json.RawMessage
+}
+
+func newApiClient(server, token string) *apiClient {
+	return &apiClient{
+		server: strings.TrimSuffix(server, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get 发送GET请求，并将Data反序列化到val
+func (receiver *apiClient) get(path string, query url.Values, val any) error {
+	body, err := receiver.getRaw(path, query)
+	if err != nil {
+		return err
+	}
+	return decode(body, val)
+}
+
+// getRaw 发送GET请求，返回原始内容
+func (receiver *apiClient) getRaw(path string, query url.Values) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return receiver.do(http.MethodGet, path, nil)
+}
+
+// stream 建立长连接，返回服务端持续推送的内容（Server-Sent Events）
+func (receiver *apiClient) stream(path string, query url.Values) (io.ReadCloser, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, receiver.server+"/admin/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("FSS-ACCESS-TOKEN", receiver.token)
+
+	// 长连接不设置超时
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		defer rsp.Body.Close()
+		body, _ := io.ReadAll(rsp.Body)
+		return nil, fmt.Errorf("%d %s", rsp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return rsp.Body, nil
+}
+
+// post 发送POST请求，并将Data反序列化到val
+func (receiver *apiClient) post(path string, dto any, val any) error {
+	if dto == nil {
+		dto = struct{}{}
+	}
+	marshal, _ := json.Marshal(dto)
+	body, err := receiver.do(http.MethodPost, path, marshal)
+	if err != nil {
+		return err
+	}
+	return decode(body, val)
+}
+
+func (receiver *apiClient) do(method, path string, payload []byte) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, receiver.server+"/admin/"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("FSS-ACCESS-TOKEN", receiver.token)
+
+	rsp, err := receiver.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		var apiRsp apiResponse
+		if json.Unmarshal(body, &apiRsp) == nil && apiRsp.StatusMessage != "" {
+			return nil, fmt.Errorf("%d %s", rsp.StatusCode, apiRsp.StatusMessage)
+		}
+		return nil, fmt.Errorf("%d %s", rsp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// decode 解析ApiResponse，失败时返回服务端的错误信息
+func decode(body []byte, val any) error {
+	var apiRsp apiResponse
+	if err := json.Unmarshal(body, &apiRsp); err != nil {
+		return fmt.Errorf("无法解析服务端的响应：%s", string(body))
+	}
+	if !apiRsp.Status {
+		return fmt.Errorf("%d %s", apiRsp.StatusCode, apiRsp.StatusMessage)
+	}
+	if val == nil || len(apiRsp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(apiRsp.Data, val)
+}
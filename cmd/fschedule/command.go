@@ -0,0 +1,406 @@
+package main
+
+import (
+	"FSchedule/domain/enum"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 持续跟踪日志时，轮询的间隔
+const tailInterval = 2 * time.Second
+
+type command struct {
+	api  *apiClient
+	json bool // 以json格式输出
+}
+
+func (receiver *command) run(args []string) error {
+	switch args[0] {
+	case "groups", "group":
+		return receiver.groups(args[1:])
+	case "clients", "client":
+		return receiver.clients(args[1:])
+	case "logs", "log":
+		return receiver.logs(args[1:])
+	case "watch":
+		return receiver.watch(args[1:])
+	case "nodes":
+		return receiver.nodes(false)
+	case "leader":
+		return receiver.nodes(true)
+	case "stepdown":
+		if err := receiver.api.post("cluster/stepdown", nil, nil); err != nil {
+			return err
+		}
+		fmt.Println("已通知Master让位，集群将重新选举")
+		return nil
+	case "export":
+		return receiver.export(args[1:])
+	case "import":
+		return receiver.importConfig(args[1:])
+	}
+	return fmt.Errorf("未知的命令：%s", args[0])
+}
+
+func (receiver *command) groups(args []string) error {
+	if len(args) == 0 {
+		return errors.New("缺少子命令：list、describe、enable、disable、trigger、kill")
+	}
+	if args[0] == "list" {
+		var lst []taskGroupView
+		return receiver.show("taskgroup/list", nil, &lst, func() {
+			receiver.table("NAME\tCAPTION\tVER\tENABLE\tCRON\tSTATUS\tCLIENT\tNEXT\tRUNS\tAVG(ms)", func(w *tabwriter.Writer) {
+				for _, item := range lst {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%t\t%s\t%s\t%s\t%s\t%d\t%d\n", item.Name, item.Caption, item.Ver, item.IsEnable, item.Cron, item.Task.Status.String(), item.Task.Client.Name, formatTime(item.NextAt), item.RunCount, item.RunSpeedAvg)
+				}
+			})
+		})
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("缺少任务组名称：groups %s <name>", args[0])
+	}
+	name := args[1]
+	switch args[0] {
+	case "describe":
+		var info taskGroupInfoView
+		return receiver.show("taskgroup/info", url.Values{"name": {name}}, &info, func() {
+			do := info.TaskGroup
+			receiver.table("", func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "Name:\t%s\n", do.Name)
+				fmt.Fprintf(w, "Caption:\t%s\n", do.Caption)
+				fmt.Fprintf(w, "Ver:\t%d\n", do.Ver)
+				fmt.Fprintf(w, "Enable:\t%t\n", do.IsEnable)
+				fmt.Fprintf(w, "Cron:\t%s\n", do.Cron)
+				fmt.Fprintf(w, "Priority:\t%d\n", do.Priority)
+				fmt.Fprintf(w, "VersionPolicy:\t%s\n", do.VersionPolicy.String())
+				fmt.Fprintf(w, "NextAt:\t%s\n", formatTime(do.NextAt))
+				fmt.Fprintf(w, "LastRunAt:\t%s\n", formatTime(do.LastRunAt))
+				fmt.Fprintf(w, "RunCount:\t%d\n", do.RunCount)
+				fmt.Fprintf(w, "RunSpeedAvg:\t%dms\n", do.RunSpeedAvg)
+				fmt.Fprintf(w, "Task:\t%d %s %d%% %s\n", do.Task.Id, do.Task.Status.String(), do.Task.Progress, do.Task.Client.Name)
+			})
+			fmt.Println()
+			receiver.table("TASK\tVER\tSTATUS\tCLIENT\tSTART\tSPEED(ms)", func(w *tabwriter.Writer) {
+				for _, task := range info.Tasks {
+					fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%d\n", task.Id, task.Ver, task.Status.String(), task.Client.Name, formatTime(task.StartAt), task.RunSpeed)
+				}
+			})
+		})
+	case "enable", "disable":
+		dto := map[string]any{"Name": name, "IsEnable": args[0] == "enable"}
+		return receiver.done(receiver.api.post("taskgroup/setenable", dto, nil), "任务组：%s 已%s", name, map[bool]string{true: "开启", false: "停止"}[args[0] == "enable"])
+	case "trigger":
+		data := make(map[string]string)
+		for _, arg := range args[2:] {
+			key, value, found := strings.Cut(arg, "=")
+			if !found {
+				return fmt.Errorf("参数格式错误：%s，应为key=value", arg)
+			}
+			data[key] = value
+		}
+		return receiver.done(receiver.api.post("taskgroup/trigger", map[string]any{"Name": name, "Data": data}, nil), "任务组：%s 已触发执行", name)
+	case "kill":
+		return receiver.done(receiver.api.post("taskgroup/kill", map[string]any{"Name": name}, nil), "任务组：%s 已终止执行中的任务", name)
+	}
+	return fmt.Errorf("未知的子命令：groups %s", args[0])
+}
+
+func (receiver *command) clients(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		var lst []clientView
+		return receiver.show("client/list", nil, &lst, func() {
+			receiver.table("ID\tNAME\tADDR\tMODE\tSTATUS\tQUEUE\tWORK\tCPU\tMEM\tACTIVATE", func(w *tabwriter.Writer) {
+				for _, item := range lst {
+					fmt.Fprintf(w, "%d\t%s\t%s:%d\t%s\t%s\t%d\t%d\t%.1f%%\t%.1f%%\t%s\n", item.Id, item.Name, item.Ip, item.Port, item.Mode.String(), item.Status.String(), item.QueueCount, item.WorkCount, item.CpuUsage, item.MemoryUsage, formatTime(item.ActivateAt))
+				}
+			})
+		})
+	}
+	if args[0] != "describe" {
+		return fmt.Errorf("未知的子命令：clients %s", args[0])
+	}
+	if len(args) < 2 {
+		return errors.New("缺少客户端ID：clients describe <id>")
+	}
+	var do clientView
+	return receiver.show("client/info", url.Values{"id": {args[1]}}, &do, func() {
+		receiver.table("", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Id:\t%d\n", do.Id)
+			fmt.Fprintf(w, "Name:\t%s\n", do.Name)
+			fmt.Fprintf(w, "Addr:\t%s:%d\n", do.Ip, do.Port)
+			fmt.Fprintf(w, "Mode:\t%s\n", do.Mode.String())
+			fmt.Fprintf(w, "Status:\t%s\n", do.Status.String())
+			fmt.Fprintf(w, "Tags:\t%s\n", strings.Join(do.Tags, ","))
+			fmt.Fprintf(w, "Queue/Work:\t%d/%d\n", do.QueueCount, do.WorkCount)
+			fmt.Fprintf(w, "Cpu/Memory:\t%.1f%%/%.1f%%\n", do.CpuUsage, do.MemoryUsage)
+			fmt.Fprintf(w, "ActivateAt:\t%s\n", formatTime(do.ActivateAt))
+		})
+		fmt.Println()
+		receiver.table("JOB\tVER", func(w *tabwriter.Writer) {
+			for _, job := range do.Jobs {
+				fmt.Fprintf(w, "%s\t%d\n", job.Name, job.Ver)
+			}
+		})
+	})
+}
+
+func (receiver *command) logs(args []string) error {
+	if len(args) > 0 && args[0] == "tail" {
+		args = args[1:]
+	}
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	taskId := fs.Int64("task", 0, "只查看指定任务的日志")
+	top := fs.Int("n", 100, "首次显示的日志数量")
+	follow := fs.Bool("f", false, "持续跟踪新的日志")
+	name, err := parseWithName(fs, args, "logs <name>")
+	if err != nil {
+		return err
+	}
+
+	var afterId int64
+	for {
+		query := url.Values{
+			"name":    {name},
+			"taskId":  {strconv.FormatInt(*taskId, 10)},
+			"afterId": {strconv.FormatInt(afterId, 10)},
+			"top":     {strconv.Itoa(*top)},
+		}
+		var lst []taskLogView
+		if err = receiver.api.get("taskgroup/logs", query, &lst); err != nil {
+			return err
+		}
+		for _, item := range lst {
+			if receiver.json {
+				marshal, _ := json.Marshal(item)
+				fmt.Println(string(marshal))
+			} else {
+				fmt.Printf("%s %-11s [%d] %s\n", item.CreateAt.Format("2006-01-02 15:04:05.000"), eumLogLevel.GetName(eumLogLevel.Enum(item.LogLevel)), item.TaskId, item.Content)
+			}
+			afterId = item.Id
+		}
+		if !*follow {
+			return nil
+		}
+		time.Sleep(tailInterval)
+	}
+}
+
+func (receiver *command) watch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	taskId := fs.Int64("task", 0, "只查看指定任务")
+	name, err := parseWithName(fs, args, "watch <name>")
+	if err != nil {
+		return err
+	}
+
+	body, err := receiver.api.stream("live/connect", url.Values{"name": {name}, "taskId": {strconv.FormatInt(*taskId, 10)}})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, found := strings.CutPrefix(scanner.Text(), "data: ")
+		if !found {
+			continue
+		}
+		if receiver.json {
+			fmt.Println(data)
+			continue
+		}
+		var event liveEventView
+		if json.Unmarshal([]byte(data), &event) != nil {
+			continue
+		}
+		at := event.At.Local().Format("2006-01-02 15:04:05.000")
+		if event.Type == enum.LiveStatus {
+			fmt.Printf("%s %-11s [%d] %s %s %d%% %s\n", at, "STATUS", event.TaskId, event.Name, event.Status.String(), event.Progress, event.ClientName)
+		} else {
+			fmt.Printf("%s %-11s [%d] %s %s\n", at, eumLogLevel.GetName(event.LogLevel), event.TaskId, event.Name, event.Content)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("服务端断开了连接")
+}
+
+func (receiver *command) nodes(onlyLeader bool) error {
+	var dto nodesView
+	return receiver.show("cluster/nodes", nil, &dto, func() {
+		if onlyLeader {
+			for _, node := range dto.Nodes {
+				if node.Id == dto.LeaderId {
+					fmt.Printf("%d %s %s:%d\n", node.Id, node.Name, node.Ip, node.Port)
+					return
+				}
+			}
+			fmt.Printf("%d\n", dto.LeaderId)
+			return
+		}
+		// 每个节点分配到的任务组数量
+		groups := make(map[int64]int)
+		for _, nodeId := range dto.Assign {
+			groups[nodeId]++
+		}
+		receiver.table("ID\tNAME\tADDR\tLEADER\tGROUPS\tACTIVATE", func(w *tabwriter.Writer) {
+			for _, node := range dto.Nodes {
+				fmt.Fprintf(w, "%d\t%s\t%s:%d\t%t\t%d\t%s\n", node.Id, node.Name, node.Ip, node.Port, node.IsLeader, groups[node.Id], formatTime(node.ActivateAt))
+			}
+		})
+	})
+}
+
+func (receiver *command) export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "yaml", "导出格式：yaml、json")
+	file := fs.String("file", "", "保存到文件（默认输出到控制台）")
+	_ = fs.Parse(args)
+
+	content, err := receiver.api.getRaw("taskgroup/export", url.Values{"format": {*format}})
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		fmt.Print(string(content))
+		return nil
+	}
+	return os.WriteFile(*file, content, 0644)
+}
+
+func (receiver *command) importConfig(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "文件格式：yaml、json（默认根据文件扩展名判断）")
+	file := fs.String("file", "", "任务组定义的文件")
+	dryRun := fs.Bool("dry-run", false, "只显示差异，不修改")
+	prune := fs.Bool("prune", false, "停止文件中不存在的任务组")
+	_ = fs.Parse(args)
+	if *file == "" {
+		return errors.New("缺少参数：--file")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	if *format == "" && strings.HasSuffix(strings.ToLower(*file), ".json") {
+		*format = "json"
+	}
+	dto := map[string]any{"Format": *format, "Content": string(content), "DryRun": *dryRun, "Prune": *prune}
+
+	var lst []struct {
+		Name   string
+		Action string
+		Diff   []struct {
+			Field  string
+			Before any
+			After  any
+		}
+	}
+	if err = receiver.api.post("taskgroup/import", dto, &lst); err != nil {
+		return err
+	}
+	if receiver.json {
+		return printJson(lst)
+	}
+	receiver.table("NAME\tACTION\tFIELD\tBEFORE\tAFTER", func(w *tabwriter.Writer) {
+		for _, item := range lst {
+			if len(item.Diff) == 0 {
+				fmt.Fprintf(w, "%s\t%s\t\t\t\n", item.Name, item.Action)
+			}
+			for _, diff := range item.Diff {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%v\n", item.Name, item.Action, diff.Field, diff.Before, diff.After)
+			}
+		}
+	})
+	if *dryRun {
+		fmt.Println("（dry-run，未做修改）")
+	}
+	return nil
+}
+
+// show 查询接口，json格式直接输出Data，否则解析到val后按表格输出
+func (receiver *command) show(path string, query url.Values, val any, table func()) error {
+	if receiver.json {
+		var raw json.RawMessage
+		if err := receiver.api.get(path, query, &raw); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		_ = json.Indent(&buf, raw, "", "  ")
+		fmt.Println(buf.String())
+		return nil
+	}
+	if err := receiver.api.get(path, query, val); err != nil {
+		return err
+	}
+	table()
+	return nil
+}
+
+// done 输出操作结果
+func (receiver *command) done(err error, format string, args ...any) error {
+	if err != nil {
+		return err
+	}
+	if receiver.json {
+		return printJson(map[string]any{"Status": true, "Message": fmt.Sprintf(format, args...)})
+	}
+	fmt.Printf(format+"\n", args...)
+	return nil
+}
+
+func (receiver *command) table(header string, fn func(w *tabwriter.Writer)) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if header != "" {
+		fmt.Fprintln(w, header)
+	}
+	fn(w)
+	_ = w.Flush()
+}
+
+// parseWithName 解析参数，第一个位置参数为任务组名称（允许出现在参数之前或之后）
+func parseWithName(fs *flag.FlagSet, args []string, usage string) (string, error) {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	_ = fs.Parse(args)
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		return "", fmt.Errorf("缺少任务组名称：%s", usage)
+	}
+	return name, nil
+}
+
+func printJson(val any) error {
+	marshal, err := json.MarshalIndent(val, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(marshal))
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() || t.Year() < 2000 {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
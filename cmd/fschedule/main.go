@@ -0,0 +1,70 @@
+// fschedule 调度中心的命令行工具，通过管理接口（/admin/）管理任务组、客户端、集群
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const usage = `fschedule 调度中心命令行工具
+
+用法：
+  fschedule [全局参数] <命令> [参数]
+
+命令：
+  groups list                          任务组列表
+  groups describe <name>               任务组详情及最近完成的任务
+  groups enable <name>                 开启任务组
+  groups disable <name>                停止任务组
+  groups trigger <name> [key=value]    立即执行一次，key=value只覆盖本次执行的参数
+  groups kill <name>                   终止执行中的任务
+  clients list                         客户端列表
+  clients describe <id>                客户端详情
+  logs <name> [--task id] [-n 100] [-f] 查看任务日志，-f 持续跟踪
+  watch [name] [--task id]             实时查看日志和任务状态变更（不指定name时订阅所有任务组）
+  nodes                                集群节点
+  leader                               当前Master节点
+  stepdown                             强制当前Master让位，重新选举
+  export [--format yaml|json] [--file path]
+                                       导出任务组的定义
+  import --file path [--format yaml|json] [--dry-run] [--prune]
+                                       导入任务组的定义
+
+全局参数：
+`
+
+func main() {
+	global := flag.NewFlagSet("fschedule", flag.ExitOnError)
+	server := global.String("server", env("FSCHEDULE_SERVER", "http://127.0.0.1:8886"), "服务端地址（环境变量FSCHEDULE_SERVER）")
+	token := global.String("token", env("FSCHEDULE_TOKEN", ""), "管理接口的token（环境变量FSCHEDULE_TOKEN）")
+	output := global.String("o", "table", "输出格式：table、json")
+	global.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		global.PrintDefaults()
+	}
+	_ = global.Parse(os.Args[1:])
+
+	args := global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	cli := &command{
+		api:  newApiClient(*server, *token),
+		json: strings.EqualFold(*output, "json"),
+	}
+	if err := cli.run(args); err != nil {
+		fmt.Fprintln(os.Stderr, "错误：", err.Error())
+		os.Exit(1)
+	}
+}
+
+func env(key string, defVal string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defVal
+}
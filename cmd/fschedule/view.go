@@ -0,0 +1,97 @@
+package main
+
+import (
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
+// 命令行只关心展示的字段，完整内容使用 -o json 查看
+
+type taskGroupView struct {
+	Name          string
+	Caption       string
+	Ver           int
+	Cron          string
+	IsEnable      bool
+	NextAt        time.Time
+	LastRunAt     time.Time
+	RunCount      int
+	RunSpeedAvg   int64
+	Priority      int
+	VersionPolicy enum.VersionPolicy
+	Task          taskView
+}
+
+type taskView struct {
+	Id       int64
+	Ver      int
+	StartAt  time.Time
+	RunAt    time.Time
+	RunSpeed int64
+	Status   enum.TaskStatus
+	Progress int
+	Client   struct {
+		Id   int64
+		Name string
+		Ip   string
+	}
+}
+
+type taskGroupInfoView struct {
+	TaskGroup taskGroupView
+	Tasks     []taskView
+}
+
+type clientView struct {
+	Id          int64
+	Name        string
+	Ip          string
+	Port        int
+	Mode        enum.ClientMode
+	Status      enum.ClientStatus
+	QueueCount  int
+	WorkCount   int
+	CpuUsage    float32
+	MemoryUsage float32
+	ActivateAt  time.Time
+	Tags        []string
+	Jobs        []struct {
+		Name string
+		Ver  int
+	}
+}
+
+type nodesView struct {
+	LeaderId int64
+	Nodes    []struct {
+		Id         int64
+		Name       string
+		Ip         string
+		Port       int
+		IsLeader   bool
+		ActivateAt time.Time
+	}
+	Assign map[string]int64
+}
+
+type taskLogView struct {
+	Id       int64
+	TaskId   int64
+	Ver      int
+	LogLevel int
+	Content  string
+	CreateAt time.Time
+}
+
+type liveEventView struct {
+	Type       enum.LiveEventType
+	Name       string
+	TaskId     int64
+	Status     enum.TaskStatus
+	Progress   int
+	ClientName string
+	LogLevel   eumLogLevel.Enum
+	Content    string
+	At         time.Time
+}
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"time"
+)
+
+type Repository interface {
+	// IsEnable 是否开启归档（未开启时，历史任务直接删除）
+	IsEnable() bool
+	// Save 归档任务及日志，返回归档的Key
+	Save(name string, lstTask collections.List[taskGroup.TaskEO], lstLog collections.List[taskLog.DomainObject]) (string, error)
+	// ToList 任务组的归档
+	ToList(name string) (collections.List[FileVO], error)
+	// Load 读取归档的任务及日志
+	Load(key string) (collections.List[taskGroup.TaskEO], collections.List[taskLog.DomainObject], error)
+}
+
+// FileVO 归档文件
+type FileVO struct {
+	Key      string    // 归档的Key（重新导入时使用）
+	Name     string    // 任务组名称
+	Format   string    // 文件格式：jsonl、parquet
+	Size     int64     // 文件大小（任务、日志合计）
+	CreateAt time.Time // 归档时间
+}
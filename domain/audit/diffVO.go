@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"FSchedule/domain/secret"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// DiffVO 变更的字段
+type DiffVO struct {
+	Field  string // 字段名称
+	Before string // 变更前的值（json）
+	After  string // 变更后的值（json）
+}
+
+// Diff 比较两个对象的第一层字段
+func Diff(before, after map[string]any) []DiffVO {
+	fields := make(map[string]struct{})
+	for field := range before {
+		fields[field] = struct{}{}
+	}
+	for field := range after {
+		fields[field] = struct{}{}
+	}
+
+	var lst []DiffVO
+	for field := range fields {
+		beforeVal, afterVal := before[field], after[field]
+		if reflect.DeepEqual(beforeVal, afterVal) {
+			continue
+		}
+		lst = append(lst, DiffVO{Field: field, Before: valueJson(beforeVal), After: valueJson(afterVal)})
+	}
+	sort.Slice(lst, func(i, j int) bool {
+		return lst[i].Field < lst[j].Field
+	})
+	return lst
+}
+
+func valueJson(val any) string {
+	if val == nil {
+		return ""
+	}
+	marshal, _ := json.Marshal(val)
+	return secret.MaskText(string(marshal))
+}
+
+// DiffObject 比较两个对象的第一层字段
+func DiffObject(before, after any) []DiffVO {
+	return Diff(toMap(before), toMap(after))
+}
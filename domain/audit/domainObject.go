@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/secret"
+	"encoding/json"
+	"github.com/farseer-go/fs/snowflake"
+	"time"
+)
+
+type DomainObject struct {
+	Id       int64          // 主键
+	Type     enum.AuditType // 审计类型
+	Action   string         // 操作名称
+	Actor    string         // 操作人（管理员、客户端、服务端节点）
+	Ip       string         // 来源IP
+	Target   string         // 操作对象（任务组名称、客户端ID）
+	Before   string         // 变更前（json）
+	After    string         // 变更后（json）
+	Diff     []DiffVO       // 变更的字段
+	CreateAt time.Time      // 操作时间
+}
+
+// New 创建审计记录，before、after为nil时表示新增、删除
+func New(auditType enum.AuditType, action, actor, ip, target string, before, after any) DomainObject {
+	do := DomainObject{
+		Id:       snowflake.GenerateId(),
+		Type:     auditType,
+		Action:   action,
+		Actor:    actor,
+		Ip:       ip,
+		Target:   target,
+		CreateAt: time.Now(),
+	}
+	beforeMap := toMap(before)
+	afterMap := toMap(after)
+	do.Before = toJson(beforeMap)
+	do.After = toJson(afterMap)
+	do.Diff = Diff(beforeMap, afterMap)
+	return do
+}
+
+// HasDiff 是否有字段变更
+func (receiver *DomainObject) HasDiff() bool {
+	return len(receiver.Diff) > 0
+}
+
+func toMap(val any) map[string]any {
+	if val == nil {
+		return nil
+	}
+	m := make(map[string]any)
+	marshal, _ := json.Marshal(val)
+	_ = json.Unmarshal(marshal, &m)
+	return m
+}
+
+func toJson(m map[string]any) string {
+	if m == nil {
+		return ""
+	}
+	marshal, _ := json.Marshal(m)
+	// 加密后的Secret参数脱敏
+	return secret.MaskText(string(marshal))
+}
@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/collections"
+	"time"
+)
+
+type Repository interface {
+	// Add 添加审计记录
+	Add(do DomainObject)
+	// ToPageList 查询审计记录
+	ToPageList(filter FilterVO, pageSize int, pageIndex int) collections.PageList[DomainObject]
+}
+
+// FilterVO 审计记录的查询条件（零值表示不过滤）
+type FilterVO struct {
+	Types   []enum.AuditType // 审计类型
+	Actor   string           // 操作人
+	Ip      string           // 来源IP
+	Target  string           // 操作对象
+	StartAt time.Time        // 开始时间
+	EndAt   time.Time        // 结束时间
+}
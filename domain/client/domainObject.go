@@ -8,6 +8,15 @@ import (
 	"time"
 )
 
+// MaxPullTimeout 拉取模式下，长轮询最长等待时间
+const MaxPullTimeout = 30 * time.Second
+
+// 拉取模式下，每隔多久保存一次活动时间
+const pullActivateInterval = 10 * time.Second
+
+// 拉取模式下，超过这个时间没有拉取任务，则判定为无法调度（长轮询 + 活动时间保存间隔，再留出网络抖动的余量）
+const pullTimeout = MaxPullTimeout + pullActivateInterval + 20*time.Second
+
 type DomainObject struct {
 	Id          int64                   // 客户端ID
 	Name        string                  // 客户端名称
@@ -23,11 +32,33 @@ type DomainObject struct {
 	ErrorCount  int                     // 错误次数
 	Jobs        collections.List[JobVO] // 客户端支持的任务
 	NeedNotice  bool                    //	是否需要通知任务组
+	Mode        enum.ClientMode         // 连接模式
+	ServerId    int64                   // 长连接所在的服务端节点
+	Tags        []string                // 客户端标签（金丝雀发布时，可指定调度给带有标签的客户端）
 }
 
 // IsNil 判断注册的客户端是否有效
 func (receiver *DomainObject) IsNil() bool {
-	return receiver.Id == 0 || receiver.Name == "" || receiver.Ip == "" || receiver.Port == 0
+	if receiver.Id == 0 || receiver.Name == "" {
+		return true
+	}
+	// 长连接、拉取模式下，服务端不需要访问客户端的地址
+	if receiver.Mode != enum.Http {
+		return false
+	}
+	return receiver.Ip == "" || receiver.Port == 0
+}
+
+// JobVer 客户端注册的任务版本
+func (receiver *DomainObject) JobVer(name string) int {
+	return receiver.Jobs.Where(func(item JobVO) bool {
+		return item.Name == name
+	}).First().Ver
+}
+
+// IsStream 是否为长连接模式
+func (receiver *DomainObject) IsStream() bool {
+	return receiver.Mode == enum.Stream
 }
 
 // IsOffline 判断客户端是否下线
@@ -53,15 +84,62 @@ func (receiver *DomainObject) Logout() {
 	receiver.NeedNotice = true
 }
 
+// IsPull 是否为拉取模式
+func (receiver *DomainObject) IsPull() bool {
+	return receiver.Mode == enum.Pull
+}
+
+// Disconnect 长连接断开，如果客户端已重连到其它节点，则不需要下线
+func (receiver *DomainObject) Disconnect(serverId int64) bool {
+	if receiver.IsNil() || receiver.IsOffline() || receiver.ServerId != serverId {
+		return false
+	}
+	receiver.Logout()
+	return true
+}
+
+// ClientCheck 根据连接模式，得到与客户端通讯的实现
+func (receiver *DomainObject) ClientCheck() IClientCheck {
+	if receiver.IsStream() {
+		return container.Resolve[IClientCheck](enum.Stream.String())
+	}
+	return container.Resolve[IClientCheck]()
+}
+
 // CheckOnline 检查客户端是否存活
 func (receiver *DomainObject) CheckOnline() {
-	status, err := container.Resolve[IClientCheck]().Check(receiver)
+	// 拉取模式，根据最后一次拉取的时间判断是否存活
+	if receiver.IsPull() {
+		if time.Since(receiver.ActivateAt) < pullTimeout {
+			receiver.setStatus(enum.Scheduler)
+		} else {
+			receiver.UnSchedule()
+		}
+		return
+	}
+
+	status, err := receiver.ClientCheck().Check(receiver)
 	receiver.updateStatus(status, err)
 }
 
+// Pull 客户端拉取任务（同时作为心跳），返回是否需要保存
+func (receiver *DomainObject) Pull() bool {
+	needSave := receiver.IsNotSchedule() || time.Since(receiver.ActivateAt) >= pullActivateInterval
+	receiver.ActivateAt = time.Now()
+	receiver.ErrorCount = 0
+	receiver.setStatus(enum.Scheduler)
+	return needSave
+}
+
+// 设置状态，状态有变化时，需要通知任务组
+func (receiver *DomainObject) setStatus(status enum.ClientStatus) {
+	receiver.NeedNotice = receiver.Status != status
+	receiver.Status = status
+}
+
 // Schedule 调度
 func (receiver *DomainObject) Schedule(task *TaskEO) bool {
-	status, err := container.Resolve[IClientCheck]().Invoke(receiver, task)
+	status, err := receiver.ClientCheck().Invoke(receiver, task)
 	receiver.updateStatus(status, err)
 
 	milliseconds := time.Since(task.StartAt).Milliseconds()
@@ -7,9 +7,13 @@ import (
 
 // TaskEO 任务记录
 type TaskEO struct {
-	Id      int64                                  // 主键
-	Caption string                                 // 任务组标题
-	Name    string                                 // 实现Job的特性名称（客户端识别哪个实现类）
-	StartAt time.Time                              // 开始时间
-	Data    collections.Dictionary[string, string] // 本次执行任务时的Data数据
+	Id             int64                                  // 主键
+	Caption        string                                 // 任务组标题
+	Name           string                                 // 实现Job的特性名称（客户端识别哪个实现类）
+	StartAt        time.Time                              // 开始时间
+	Data           collections.Dictionary[string, string] // 本次执行任务时的Data数据
+	TraceParent    string                                 // 调度链路（W3C traceparent），客户端上报时带回
+	IdempotencyKey string                                 // 幂等键（任务组+计划执行时间），重试、故障转移时不变，客户端可据此去重
+	Attempt        int                                    // 第几次下发，客户端上报时带回
+	Checkpoint     string                                 // 上一次中断时保存的断点，不为空时从断点续跑
 }
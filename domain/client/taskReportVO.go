@@ -13,4 +13,6 @@ type TaskReportVO struct {
 	Progress     int                                    // 当前进度
 	Status       enum.TaskStatus                        // 执行状态
 	RunSpeed     int64                                  // 执行速度
+	TraceParent  string                                 // 调度链路（W3C traceparent）
+	Attempt      int                                    // 第几次下发（小于任务当前的下发次数时，拒绝上报）
 }
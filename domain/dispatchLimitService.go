@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"FSchedule/domain/client"
+	"FSchedule/domain/schedule"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/parse"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// 超过限流或没有可调度的客户端，排队等待调度的任务组
+var pendingList = collections.NewDictionary[string, *TaskGroupMonitor]()
+
+// 当前节点的排队统计（累计值）
+var queuedTotal, dequeuedTotal, queueWaitTotal atomic.Int64
+
+// ClientMaxWorking 每个客户端最多同时执行的任务数量（0不限制）
+func ClientMaxWorking() int {
+	return configure.GetInt("FSchedule.Limit.ClientMaxWorking")
+}
+
+// DispatchPerSecond 集群每秒最多调度的任务数量（0不限制）
+func DispatchPerSecond() int {
+	return configure.GetInt("FSchedule.Limit.DispatchPerSecond")
+}
+
+// NamespaceMaxWorking 命名空间最多同时执行的任务数量（0不限制）
+func NamespaceMaxWorking(namespace string) int {
+	if namespace == "" {
+		return 0
+	}
+	return parse.Convert(configure.GetSubNodes("FSchedule.Limit.Namespace")[namespace], 0)
+}
+
+// ClientWorkingCount 客户端正在执行的任务数量（取客户端上报与集群统计的最大值）
+func ClientWorkingCount(clientDO *client.DomainObject) int {
+	count := container.Resolve[schedule.Repository]().ClientWorkingCount(clientDO.Id)
+	if reportCount := clientDO.WorkCount + clientDO.QueueCount; reportCount > count {
+		return reportCount
+	}
+	return count
+}
+
+// NamespaceWorkingCount 集群中命名空间下正在执行的任务数量（包括并行执行中的任务）
+func NamespaceWorkingCount(namespace string) int {
+	return container.Resolve[schedule.Repository]().NamespaceWorkingCount(namespace)
+}
+
+// IsClientFull 客户端是否达到最大执行数量
+func IsClientFull(clientDO *client.DomainObject) bool {
+	maxWorking := ClientMaxWorking()
+	return maxWorking > 0 && ClientWorkingCount(clientDO) >= maxWorking
+}
+
+// PendingCount 排队等待调度的任务组数量
+func PendingCount() int {
+	return pendingList.Count()
+}
+
+// PendingMaxWaitTime 排队中的任务组，最长的等待时间（毫秒）
+func PendingMaxWaitTime() int64 {
+	var maxWaitTime int64
+	for _, monitor := range pendingList.Values().ToArray() {
+		if waitTime := time.Since(monitor.Task.QueueAt).Milliseconds(); waitTime > maxWaitTime {
+			maxWaitTime = waitTime
+		}
+	}
+	return maxWaitTime
+}
+
+// QueueStats 当前节点进入排队的次数、结束排队的次数、结束排队的累计等待耗时（毫秒）
+func QueueStats() (queued int64, dequeued int64, waitTime int64) {
+	return queuedTotal.Load(), dequeuedTotal.Load(), queueWaitTotal.Load()
+}
+
+// DispatchPending 按排队顺序，通知未超过限流的任务组重新调度
+func DispatchPending() {
+	// 优先级高的先调度，相同优先级时，排队早的先调度
+	lst := pendingList.Values().ToArray()
+	sort.Slice(lst, func(i, j int) bool {
+		if lst[i].Priority != lst[j].Priority {
+			return lst[i].Priority > lst[j].Priority
+		}
+		return lst[i].Task.QueueAt.Before(lst[j].Task.QueueAt)
+	})
+
+	for _, monitor := range lst {
+		// 已被客户端拉取或重新调度
+		if !monitor.Task.IsQueued() {
+			pendingList.Remove(monitor.Name)
+			continue
+		}
+		if !monitor.canDispatch() {
+			continue
+		}
+		pendingList.Remove(monitor.Name)
+		flog.Debugf("任务组：%s 结束排队，等待时间：%d ms", monitor.Name, time.Since(monitor.Task.QueueAt).Milliseconds())
+		monitor.notifyDispatch()
+	}
+}
@@ -0,0 +1,28 @@
+package enum
+
+// AuditType 审计类型
+type AuditType int
+
+const (
+	Admin     AuditType = iota // 管理员操作
+	Registry                   // 客户端注册
+	Logout                     // 客户端下线
+	UpdateVer                  // 任务组版本变更
+	Election                   // 选举Master
+)
+
+func (e AuditType) String() string {
+	switch e {
+	case Admin:
+		return "Admin"
+	case Registry:
+		return "Registry"
+	case Logout:
+		return "Logout"
+	case UpdateVer:
+		return "UpdateVer"
+	case Election:
+		return "Election"
+	}
+	return "Admin"
+}
@@ -0,0 +1,21 @@
+package enum
+
+type ClientMode int
+
+const (
+	Http   ClientMode = iota // 服务端通过http请求客户端
+	Stream                   // 客户端与服务端保持长连接，服务端通过长连接下发指令
+	Pull                     // 客户端主动拉取到期的任务
+)
+
+func (e ClientMode) String() string {
+	switch e {
+	case Http:
+		return "Http"
+	case Stream:
+		return "Stream"
+	case Pull:
+		return "Pull"
+	}
+	return "Http"
+}
@@ -9,3 +9,19 @@ const (
 	StopSchedule                     // 拒绝调度（客户端在忙）
 	Offline                          // 离线
 )
+
+func (e ClientStatus) String() string {
+	switch e {
+	case Online:
+		return "Online"
+	case UnSchedule:
+		return "UnSchedule"
+	case Scheduler:
+		return "Scheduler"
+	case StopSchedule:
+		return "StopSchedule"
+	case Offline:
+		return "Offline"
+	}
+	return "Online"
+}
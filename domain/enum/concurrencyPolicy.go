@@ -0,0 +1,25 @@
+package enum
+
+// ConcurrencyPolicy 任务执行中到达下一个执行周期时的处理策略
+type ConcurrencyPolicy int
+
+const (
+	Delay   ConcurrencyPolicy = iota // 不处理，下一个执行周期延迟到任务完成后（默认）
+	Forbid                           // 禁止并行，跳过本次周期（记录为跳过）
+	Allow                            // 允许并行，最多MaxParallel个任务同时执行
+	Replace                          // 终止执行中的任务，重新开始
+)
+
+func (e ConcurrencyPolicy) String() string {
+	switch e {
+	case Delay:
+		return "Delay"
+	case Forbid:
+		return "Forbid"
+	case Allow:
+		return "Allow"
+	case Replace:
+		return "Replace"
+	}
+	return "Delay"
+}
@@ -0,0 +1,19 @@
+package enum
+
+// LiveEventType 实时推送的事件类型
+type LiveEventType int
+
+const (
+	LiveLog    LiveEventType = iota // 任务日志
+	LiveStatus                      // 任务状态变更
+)
+
+func (e LiveEventType) String() string {
+	switch e {
+	case LiveLog:
+		return "log"
+	case LiveStatus:
+		return "status"
+	}
+	return "log"
+}
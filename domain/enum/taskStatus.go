@@ -9,6 +9,7 @@ const (
 	Working                        //  执行中
 	Fail                           //  失败
 	Success                        //  完成
+	Skip                           //  跳过（上一次任务仍在执行）
 )
 
 func (e TaskStatus) String() string {
@@ -25,6 +26,29 @@ func (e TaskStatus) String() string {
 		return "Fail"
 	case Success:
 		return "Success"
+	case Skip:
+		return "Skip"
 	}
 	return "None"
 }
+
+// 允许的状态变更（Success、Fail、Skip为终态，不能再变更）
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	None:         {Scheduling, Fail, Skip},
+	Scheduling:   {Working, ScheduleFail, Success, Fail},
+	ScheduleFail: {Scheduling, Working, Success, Fail},
+	Working:      {ScheduleFail, Success, Fail},
+}
+
+// CanTransit 是否允许变更为指定状态（状态不变时允许，如执行中上报进度）
+func (e TaskStatus) CanTransit(to TaskStatus) bool {
+	if e == to {
+		return true
+	}
+	for _, status := range taskTransitions[e] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package enum
+
+// VersionPolicy 任务组调度客户端版本的策略
+type VersionPolicy int
+
+const (
+	Fallback      VersionPolicy = iota // 优先当前版本，没有时依次降级到旧版本
+	Strict                             // 只调度给当前版本的客户端
+	AllowPrevious                      // 允许调度给前N个版本的客户端
+	Canary                             // 按百分比调度给当前版本，其余调度给旧版本
+)
+
+func (e VersionPolicy) String() string {
+	switch e {
+	case Fallback:
+		return "Fallback"
+	case Strict:
+		return "Strict"
+	case AllowPrevious:
+		return "AllowPrevious"
+	case Canary:
+		return "Canary"
+	}
+	return "Fallback"
+}
@@ -65,7 +65,8 @@ func MonitorClientPush(clientDO *client.DomainObject) {
 // checkOnline 异步检查客户端在线状态
 func (receiver *ClientMonitor) checkOnline() {
 	for {
-		if receiver.client.IsOffline() {
+		// 长连接模式，由连接的存活状态判断客户端是否在线；失去Master后，由新的Master检查
+		if receiver.client.IsOffline() || receiver.client.IsStream() || !serverNode.IsLeaderNode {
 			return
 		}
 		checkTime := 60 * time.Second
@@ -5,17 +5,29 @@ import (
 	"FSchedule/domain/enum"
 	"FSchedule/domain/schedule"
 	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/tracing"
+	"context"
 	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
 	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/fs/timingWheel"
+	"sort"
+	"sync/atomic"
 	"time"
 )
 
 // 加入到监控的列表
 var taskGroupList = collections.NewDictionary[string, *TaskGroupMonitor]()
 
+// 节点关闭中，不再调度任务组
+var isStopped atomic.Bool
+
+// 查询客户端任务状态连续失败达到该次数，且持续超过statusFailDuration时，按客户端下线处理
+const maxStatusFailCount = 3
+const statusFailDuration = 30 * time.Second
+
 // MonitorTaskGroupPush 将最新的任务组信息，推送到监控线程
 func MonitorTaskGroupPush(taskGroupDO *taskGroup.DomainObject) {
 	// 新的任务组不再当前列表，说明被其它节点处理了。
@@ -49,15 +61,24 @@ func ClientUpdate(clientDO *client.DomainObject) {
 
 // TaskGroupMonitor 等待任务执行
 type TaskGroupMonitor struct {
-	SchedulerEventBus    core.IEvent                                         `inject:"TaskScheduler"` // 任务调度事件
-	FinishEventBus       core.IEvent                                         `inject:"TaskFinish"`    // 任务完成
-	CheckWorkingEventBus core.IEvent                                         `inject:"CheckWorking"`  // 检查进行中的任务
+	SchedulerEventBus    core.IEvent                                         `inject:"TaskScheduler"`   // 任务调度事件
+	FinishEventBus       core.IEvent                                         `inject:"TaskFinish"`      // 任务完成
+	CheckWorkingEventBus core.IEvent                                         `inject:"CheckWorking"`    // 检查进行中的任务
+	OverlapEventBus      core.IEvent                                         `inject:"TaskOverlap"`     // 任务执行中到达下一个执行周期
+	ReleaseEventBus      core.IEvent                                         `inject:"ScheduleRelease"` // 释放调度锁
 	ScheduleRepository   schedule.Repository                                 // 锁
 	clients              collections.Dictionary[int64, *client.DomainObject] // 客户端列表
 	updated              chan struct{}                                       // 数据有更新，让流程重置
+	dispatch             chan struct{}                                       // 结束排队，重新调度
+	dispatched           atomic.Bool                                         // 结束排队时已占用调度名额
 	curClient            *client.DomainObject                                // 当前调度的客户端
 	isWorking            bool                                                // 是否进入工作状态
 	isReadWork           bool                                                // 是否进入抢锁中（false：任务组enable=false、没有客户端）
+	isHandoff            bool                                                // 已分配给其它节点，在两次执行之间交出调度
+	scheduleCtx          context.Context                                     // 持有调度锁期间有效，失去锁时取消
+	statusFailTaskId     int64                                               // 查询任务状态连续失败的任务
+	statusFailCount      int                                                 // 查询任务状态连续失败的次数
+	statusFailAt         time.Time                                           // 查询任务状态第一次失败的时间
 	*taskGroup.DomainObject
 }
 
@@ -66,6 +87,7 @@ func newMonitor(do *taskGroup.DomainObject) *TaskGroupMonitor {
 	return container.ResolveIns(&TaskGroupMonitor{
 		DomainObject: do,
 		updated:      make(chan struct{}, 1000),
+		dispatch:     make(chan struct{}, 1),
 		clients:      collections.NewDictionary[int64, *client.DomainObject](),
 	})
 }
@@ -80,18 +102,61 @@ func (receiver *TaskGroupMonitor) Start() {
 
 	// 抢占锁，谁抢到，谁负责这个任务组的调度
 	receiver.isReadWork = true
-	receiver.ScheduleRepository.Schedule(receiver.Name, func() {
+	isLost := false
+	receiver.ScheduleRepository.Schedule(receiver.Name, func(ctx context.Context) {
+		receiver.scheduleCtx = ctx
 		receiver.isWorking = true
 		flog.Infof("任务组：%s ver:%s 加入调度线程", flog.Blue(receiver.Name), flog.Yellow(receiver.Ver))
+		// 失去调度锁时，唤醒调度线程退出
+		go func() {
+			<-ctx.Done()
+			receiver.updateNotice()
+		}()
+		// 接管其它节点排队中的任务
+		if receiver.Task.IsQueued() {
+			pendingList.Add(receiver.Name, receiver)
+		}
 		for {
 			// 清空更新队列
 			receiver.updated = make(chan struct{}, 1000)
 
+			// 节点关闭，交由其它节点调度
+			if isStopped.Load() {
+				pendingList.Remove(receiver.Name)
+				receiver.isWorking = false
+				flog.Infof("任务组：%s 退出调度线程", flog.Blue(receiver.Name))
+				return
+			}
+
+			// 失去调度锁（已被其它节点接管），停止调度
+			if receiver.isLost() {
+				pendingList.Remove(receiver.Name)
+				receiver.isWorking = false
+				isLost = true
+				flog.Warningf("任务组：%s 失去调度锁，退出调度线程", flog.Blue(receiver.Name))
+				return
+			}
+
+			// 分配给了其它节点，在等待下一次执行时交出调度
+			if receiver.isHandoff && (receiver.Task.Status == enum.None || receiver.Task.Status == enum.ScheduleFail) {
+				receiver.isWorking = false
+				if receiver.ScheduleRepository.Unschedule(receiver.Name) {
+					_ = receiver.ReleaseEventBus.Publish(fs.AppId)
+				}
+				flog.Infof("任务组：%s 交由其它节点调度", flog.Blue(receiver.Name))
+				return
+			}
+
 			switch receiver.Task.Status {
 			case enum.None, enum.ScheduleFail: // 如果调度失败状态，需要重新调度
 				// 等待时间达了之后，开始调度
 				receiver.waitStart()
 			case enum.Scheduling:
+				// 超过限流或没有可调度的客户端时，排队等待调度
+				if receiver.Task.IsQueued() {
+					receiver.waitDispatch()
+					continue
+				}
 				// 等待更新即可
 				flog.Debugf("任务组：%s 等待更新", receiver.Name)
 				<-receiver.updated
@@ -103,12 +168,23 @@ func (receiver *TaskGroupMonitor) Start() {
 			}
 		}
 	})
+
+	// 交出调度、失去调度锁后，作为备用节点重新参与抢占
+	if (receiver.isHandoff || isLost) && !isStopped.Load() {
+		receiver.isHandoff = false
+		go receiver.Start()
+	}
+}
+
+// 是否已失去调度锁
+func (receiver *TaskGroupMonitor) isLost() bool {
+	return receiver.scheduleCtx != nil && receiver.scheduleCtx.Err() != nil
 }
 
 // 等待开始
 func (receiver *TaskGroupMonitor) waitStart() {
 	for {
-		if receiver.Task.Status != enum.None && receiver.Task.Status != enum.ScheduleFail {
+		if isStopped.Load() || receiver.isHandoff || receiver.isLost() || (receiver.Task.Status != enum.None && receiver.Task.Status != enum.ScheduleFail) {
 			return
 		}
 
@@ -145,31 +221,144 @@ func (receiver *TaskGroupMonitor) waitScheduler() {
 	select {
 	case <-timingWheel.AddTime(receiver.Task.StartAt.Add(-200 * time.Millisecond)).C: // 执行时间到了，准开始调度
 		// 提前了100ms进到这里。
+		// 每次执行的调度链路从这里开始
+		span := tracing.Start("", "waitScheduler", tracing.Task(receiver.Name, receiver.Task.Id)...)
+		receiver.Task.TraceParent = span.TraceParent()
 		receiver.Task.Scheduling()
 		if m := time.Since(receiver.Task.StartAt).Microseconds(); m > 0 {
 			flog.Debugf("任务组：%s %d 发布调度事件，延迟：%d us", receiver.Name, receiver.Task.Id, time.Since(receiver.Task.StartAt).Microseconds())
 		}
 		_ = receiver.SchedulerEventBus.Publish(receiver)
+		span.End()
 	case <-receiver.updated:
 		flog.Debugf("任务组：%s %d 有更新", receiver.Name, receiver.Task.Id)
 	}
 }
 
+// 排队等待调度
+func (receiver *TaskGroupMonitor) waitDispatch() {
+	flog.Debugf("任务组：%s 排队等待调度", receiver.Name)
+	select {
+	case <-receiver.dispatch:
+		// 已被客户端拉取或重新调度，名额留给下一次调度
+		if !receiver.Task.IsQueued() {
+			return
+		}
+		dequeuedTotal.Add(1)
+		queueWaitTotal.Add(time.Since(receiver.Task.QueueAt).Milliseconds())
+		receiver.Task.Dequeue()
+		_ = receiver.SchedulerEventBus.Publish(receiver)
+	case <-receiver.updated:
+		// 任务组停止后，退出排队
+		if !receiver.IsEnable {
+			pendingList.Remove(receiver.Name)
+			receiver.Task.Dequeue()
+			receiver.ScheduleFail("任务组已停止，退出排队")
+		}
+	}
+}
+
+// 结束排队：占用调度名额后通知调度线程（同一时间最多一个通知，不会阻塞）
+func (receiver *TaskGroupMonitor) notifyDispatch() {
+	receiver.dispatched.Store(true)
+	select {
+	case receiver.dispatch <- struct{}{}:
+	default: // 已有未处理的通知，调度线程会处理
+	}
+}
+
+// Queue 加入排队
+func (receiver *TaskGroupMonitor) Queue() {
+	if !receiver.Task.IsQueued() {
+		queuedTotal.Add(1)
+	}
+	receiver.Task.Queue()
+	pendingList.Add(receiver.Name, receiver)
+}
+
+// StatusFail 查询客户端任务状态失败，连续失败的次数、持续时长都达到时返回true（按客户端下线处理）
+func (receiver *TaskGroupMonitor) StatusFail() bool {
+	if receiver.statusFailTaskId != receiver.Task.Id || receiver.statusFailCount == 0 {
+		receiver.statusFailTaskId = receiver.Task.Id
+		receiver.statusFailCount = 0
+		receiver.statusFailAt = time.Now()
+	}
+	receiver.statusFailCount++
+	return receiver.statusFailCount >= maxStatusFailCount && time.Since(receiver.statusFailAt) >= statusFailDuration
+}
+
+// StatusSuccess 查询客户端任务状态成功，清除连续失败的记录
+func (receiver *TaskGroupMonitor) StatusSuccess() {
+	receiver.statusFailCount = 0
+}
+
+// TryDispatch 检查命名空间配额、客户端并发、集群调度速率，未超过限流时占用一次调度名额
+func (receiver *TaskGroupMonitor) TryDispatch() bool {
+	// 从排队中出来的，已占用过名额
+	if receiver.dispatched.CompareAndSwap(true, false) {
+		return true
+	}
+	return receiver.canDispatch()
+}
+
+// 是否可以调度（调度速率放在最后判断，避免白占名额）
+func (receiver *TaskGroupMonitor) canDispatch() bool {
+	if maxWorking := NamespaceMaxWorking(receiver.Namespace); maxWorking > 0 && NamespaceWorkingCount(receiver.Namespace) >= maxWorking {
+		return false
+	}
+	if !receiver.hasEligibleClient() {
+		return false
+	}
+	if maxDispatch := DispatchPerSecond(); maxDispatch > 0 && receiver.ScheduleRepository.IncrDispatch(time.Now().Unix()) > int64(maxDispatch) {
+		return false
+	}
+	return true
+}
+
+// 是否有接受调度、且未达到最大执行数量的客户端
+func (receiver *TaskGroupMonitor) hasEligibleClient() bool {
+	return receiver.clients.Values().Where(func(item *client.DomainObject) bool {
+		return item.Status == enum.Scheduler && (item.IsPull() || !IsClientFull(item))
+	}).Any()
+}
+
 // 等待完成
 func (receiver *TaskGroupMonitor) waitWorking() {
-	if receiver.curClient == nil || receiver.curClient.IsNil() || receiver.curClient.IsOffline() {
-		flog.Debugf("任务组：%s 当前客户端已离线", receiver.Name)
-		_ = receiver.CheckWorkingEventBus.Publish(receiver)
-		return
+	// 客户端拉取的任务，由租约判断任务是否仍在执行
+	var timer *timingWheel.Timer
+	if receiver.Task.IsLeased() {
+		flog.Debugf("任务组：%s 等待租约到期", receiver.Name)
+		timer = timingWheel.AddTime(receiver.Task.LeaseAt)
+	} else {
+		if receiver.curClient == nil || receiver.curClient.IsNil() || receiver.curClient.IsOffline() {
+			flog.Debugf("任务组：%s 当前客户端已离线", receiver.Name)
+			_ = receiver.CheckWorkingEventBus.Publish(receiver)
+			return
+		}
+
+		flog.Debugf("任务组：%s 等待客户端执行完成", receiver.Name)
+		timer = timingWheel.Add(time.Duration(receiver.RunSpeedAvg+3000) * time.Millisecond)
+	}
+
+	// 任务执行中，到达下一个执行周期时，按并发策略处理
+	var tickC chan time.Time
+	tickAt := receiver.NextTickAt()
+	if !tickAt.IsZero() {
+		tickTimer := timingWheel.AddTime(tickAt)
+		defer tickTimer.Stop()
+		tickC = tickTimer.C
 	}
 
-	flog.Debugf("任务组：%s 等待客户端执行完成", receiver.Name)
-	timer := timingWheel.Add(time.Duration(receiver.RunSpeedAvg+3000) * time.Millisecond)
 	// 这里用循环是为了，任何的更新，如果仍处于Working状态，则不需要跳到外面重新执行
 	select {
 	case <-timer.C: // 每隔60秒，主动向客户端询问任务状态
 		flog.Debugf("任务组：%s 主动向客户端询问任务状态", receiver.Name)
 		_ = receiver.CheckWorkingEventBus.Publish(receiver)
+	case <-tickC:
+		flog.Debugf("任务组：%s 任务执行中，到达下一个执行周期：%s", receiver.Name, tickAt.Format(time.DateTime))
+		timer.Stop()
+		receiver.Tick(tickAt)
+		_ = receiver.OverlapEventBus.Publish(receiver)
 	case <-receiver.updated:
 		timer.Stop()
 	}
@@ -208,10 +397,10 @@ func (receiver *TaskGroupMonitor) updateClient(newData *client.DomainObject) {
 // PollingClient 轮询的方式取到客户端
 func (receiver *TaskGroupMonitor) PollingClient() *client.DomainObject {
 	lst := receiver.clients.Values()
-	for ver := receiver.Ver; ver > 0; ver-- {
+	for _, ver := range receiver.DispatchVersions() {
 		// 使用轮询方式，根据调度时间排序，取最晚没调度的客户端
 		receiver.curClient = lst.Where(func(item *client.DomainObject) bool {
-			return item.Status == enum.Scheduler && item.Jobs.Where(func(jobVO client.JobVO) bool {
+			return item.Status == enum.Scheduler && !item.IsPull() && !IsClientFull(item) && receiver.IsCanaryClient(ver, item.Tags) && item.Jobs.Where(func(jobVO client.JobVO) bool {
 				return jobVO.Name == receiver.Name && jobVO.Ver == ver
 			}).Any()
 		}).OrderBy(func(item *client.DomainObject) any {
@@ -226,6 +415,33 @@ func (receiver *TaskGroupMonitor) PollingClient() *client.DomainObject {
 	return receiver.curClient
 }
 
+// RestoreClient 任务已下发给客户端（重试、故障转移），恢复为当前调度的客户端，客户端不存在时返回nil
+func (receiver *TaskGroupMonitor) RestoreClient(clientId int64) *client.DomainObject {
+	clientDO := receiver.clients.GetValue(clientId)
+	if clientDO != nil {
+		receiver.curClient = clientDO
+	}
+	return clientDO
+}
+
+// HasPullClient 是否有拉取模式的客户端
+func (receiver *TaskGroupMonitor) HasPullClient() bool {
+	return receiver.clients.Values().Where(func(item *client.DomainObject) bool {
+		return item.Status == enum.Scheduler && item.IsPull() && item.Jobs.Where(func(jobVO client.JobVO) bool {
+			return jobVO.Name == receiver.Name && receiver.AllowVer(jobVO.Ver)
+		}).Any()
+	}).Any()
+}
+
+// KillTask 通知执行任务的客户端终止任务（客户端不存在、已下线或拉取模式时忽略）
+func (receiver *TaskGroupMonitor) KillTask() {
+	clientDO := receiver.clients.GetValue(receiver.Task.Client.Id)
+	if clientDO == nil || clientDO.IsNil() || clientDO.IsOffline() || clientDO.IsPull() {
+		return
+	}
+	clientDO.ClientCheck().Kill(clientDO, receiver.Task.Id)
+}
+
 // GetClient 获取客户端
 func (receiver *TaskGroupMonitor) GetClient() *client.DomainObject {
 	return receiver.curClient
@@ -264,6 +480,51 @@ func TaskGroupEnableCount() int {
 	}).Count()
 }
 
+// Handoff 按分配结果，将分配给其它节点的任务组交出调度（assign：任务组对应的节点），返回交出的数量
+func Handoff(assign map[string]int64) int {
+	count := 0
+	for _, monitor := range taskGroupList.Values().ToArray() {
+		nodeId := assign[monitor.Name]
+		switch {
+		case nodeId == fs.AppId:
+			monitor.isHandoff = false
+		case nodeId > 0 && monitor.isWorking && !monitor.isHandoff:
+			monitor.isHandoff = true
+			monitor.updateNotice()
+			count++
+		}
+	}
+	return count
+}
+
+// StopMonitor 节点关闭时，停止调度任务组，等待正在进行的调度完成（超时返回false）
+func StopMonitor(timeout time.Duration) bool {
+	isStopped.Store(true)
+	for _, monitor := range taskGroupList.Values().ToArray() {
+		if monitor.isWorking {
+			monitor.updateNotice()
+		}
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if len(WorkingTaskGroups()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingTaskGroups 由当前节点负责调度的任务组
+func WorkingTaskGroups() []string {
+	var names []string
+	for _, monitor := range taskGroupList.Values().ToArray() {
+		if monitor.isWorking {
+			names = append(names, monitor.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 //func GoID() uint64 {
 //	b := make([]byte, 64)
 //	b = b[:runtime.Stack(b, false)]
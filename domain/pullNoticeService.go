@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"FSchedule/domain/taskGroup"
+	"sync"
+)
+
+// 拉取模式下，有任务可拉取时关闭当前通道，唤醒全部长轮询
+var pullNotice = make(chan struct{})
+var pullNoticeLock sync.Mutex
+
+// PullNotice 获取当前的通知通道，有任务可拉取时会被关闭
+func PullNotice() <-chan struct{} {
+	pullNoticeLock.Lock()
+	defer pullNoticeLock.Unlock()
+	return pullNotice
+}
+
+// PullNoticePush 任务组有更新，可拉取时唤醒长轮询
+func PullNoticePush(taskGroupDO *taskGroup.DomainObject) {
+	if !taskGroupDO.CanPull() {
+		return
+	}
+	pullNoticeLock.Lock()
+	defer pullNoticeLock.Unlock()
+	close(pullNotice)
+	pullNotice = make(chan struct{})
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// 每个节点在哈希环上的虚拟节点数量
+const virtualNodes = 160
+
+// 节点的容量：平均负载的1.25倍，超过后顺延到哈希环的下一个节点
+const loadFactor = 1.25
+
+type ringNode struct {
+	hash   uint32
+	nodeId int64
+}
+
+// Balance 使用有界负载的一致性哈希，将任务组分配给节点（weights：任务组的权重），返回任务组对应的节点
+func Balance(nodeIds []int64, weights map[string]int64) map[string]int64 {
+	assign := make(map[string]int64, len(weights))
+	if len(nodeIds) == 0 {
+		return assign
+	}
+
+	// 构建哈希环
+	ring := make([]ringNode, 0, len(nodeIds)*virtualNodes)
+	for _, nodeId := range nodeIds {
+		for i := 0; i < virtualNodes; i++ {
+			ring = append(ring, ringNode{hash: hashKey(strconv.FormatInt(nodeId, 10) + "#" + strconv.Itoa(i)), nodeId: nodeId})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		if ring[i].hash == ring[j].hash {
+			return ring[i].nodeId < ring[j].nodeId
+		}
+		return ring[i].hash < ring[j].hash
+	})
+
+	// 权重大的任务组先分配，使各节点的负载更均衡
+	names := make([]string, 0, len(weights))
+	var total int64
+	for name, weight := range weights {
+		names = append(names, name)
+		total += weight
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if weights[names[i]] == weights[names[j]] {
+			return names[i] < names[j]
+		}
+		return weights[names[i]] > weights[names[j]]
+	})
+
+	capacity := float64(total) / float64(len(nodeIds)) * loadFactor
+	loads := make(map[int64]int64, len(nodeIds))
+	for _, name := range names {
+		weight := weights[name]
+		hash := hashKey(name)
+		start := sort.Search(len(ring), func(i int) bool { return ring[i].hash >= hash })
+
+		// 顺时针找到第一个未超过容量的节点，都超过时分配给负载最小的节点
+		var nodeId int64
+		for i := 0; i < len(ring); i++ {
+			node := ring[(start+i)%len(ring)]
+			if float64(loads[node.nodeId]+weight) <= capacity {
+				nodeId = node.nodeId
+				break
+			}
+		}
+		if nodeId == 0 {
+			for _, id := range nodeIds {
+				if nodeId == 0 || loads[id] < loads[nodeId] {
+					nodeId = id
+				}
+			}
+		}
+		loads[nodeId] += weight
+		assign[name] = nodeId
+	}
+	return assign
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
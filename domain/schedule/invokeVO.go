@@ -0,0 +1,8 @@
+package schedule
+
+// InvokeVO 任务的下发记录，重试、故障转移时用于去重
+type InvokeVO struct {
+	TaskId   int64 // 任务ID
+	ClientId int64 // 下发成功的客户端（0：下发中）
+	Attempt  int   // 第几次下发（下发中、下发成功的次数）
+}
@@ -1,16 +1,47 @@
 package schedule
 
 import (
+	"context"
 	"github.com/farseer-go/fs/core"
 )
 
 type Repository interface {
 	// ScheduleLock 创建调度锁
 	ScheduleLock(name string, taskId int64) core.ILock
-	// Election 选举锁
-	Election(fn func())
-	// Schedule 调度
-	Schedule(name string, fn func())
+	// Election 选举Master，当选后执行fn，失去Master时ctx取消，并重新参与选举
+	Election(fn func(ctx context.Context))
+	// StepDown 当前节点是Master时让位（先停止Master相关的任务，再释放锁），由其它节点重新选举
+	StepDown() bool
+	// Schedule 抢占任务组的调度锁，抢到后执行fn（自动续约），失去调度锁时ctx取消
+	Schedule(name string, fn func(ctx context.Context))
+	// CheckFence 当前节点是否仍持有任务组的调度锁（本地续约有效，且令牌仍是最新的，redis异常时返回false）
+	CheckFence(name string) bool
+	// CheckLeader 校验当前节点Master的令牌是否仍是最新的（redis异常时返回false）
+	CheckLeader() bool
+	// Unschedule 释放任务组的调度锁，交由其它节点调度
+	Unschedule(name string) bool
+	// SaveAssign 保存任务组分配给的节点
+	SaveAssign(assign map[string]int64)
+	// GetAssign 获取任务组分配给的节点
+	GetAssign() map[string]int64
+	// ClaimInvoke 下发任务前按下发次数占用幂等键，已下发成功、相同或之后的下发次数正在下发中时返回false及已有的下发记录
+	ClaimInvoke(key string, taskId int64, attempt int) (InvokeVO, bool)
+	// CompleteInvoke 下发完成，成功时记录下发的客户端，失败时释放该下发次数的占用
+	CompleteInvoke(key string, invoke InvokeVO, success bool)
+	// Release 节点关闭时，释放当前节点持有的调度锁及Master，不再参与选举，返回释放的调度锁数量
+	Release() int
+	// NotifyRelease 其它节点释放了锁，等待中的选举立即重新抢占
+	NotifyRelease()
 	// GetLeaderId 获取master集群ID
 	GetLeaderId() int64
+	// IncrDispatch 累加集群当前秒的调度次数，返回累加后的次数
+	IncrDispatch(second int64) int64
+	// AddWorking 记录集群中执行中的任务（下发成功、被客户端拉取时）
+	AddWorking(taskId int64, clientId int64, namespace string)
+	// RemoveWorking 任务结束执行时，移除执行中的记录
+	RemoveWorking(taskId int64)
+	// ClientWorkingCount 集群中客户端正在执行的任务数量
+	ClientWorkingCount(clientId int64) int
+	// NamespaceWorkingCount 集群中命名空间下正在执行的任务数量
+	NamespaceWorkingCount(namespace string) int
 }
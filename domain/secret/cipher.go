@@ -0,0 +1,66 @@
+package secret
+
+import (
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/container"
+	"regexp"
+	"strings"
+)
+
+// Prefix 加密后的参数前缀（enc:<密钥ID>:<密文>）
+const Prefix = "enc:"
+
+// Mask 脱敏后显示的内容
+const Mask = "******"
+
+// 文本中加密后的参数
+var cipherRegexp = regexp.MustCompile(`enc:[\w-]+:[A-Za-z0-9+/=]+`)
+
+// Cipher 加密、解密Secret参数（密钥由服务端配置，支持轮换）
+type Cipher interface {
+	// IsEnable 是否配置了密钥
+	IsEnable() bool
+	// Encrypt 使用当前密钥加密
+	Encrypt(plain string) (string, error)
+	// Decrypt 使用加密时的密钥解密
+	Decrypt(value string) (string, error)
+	// NeedRotate 不是使用当前密钥加密的，需要重新加密
+	NeedRotate(value string) bool
+}
+
+// GetCipher 服务端配置的加密方式（未注册时返回nil）
+func GetCipher() Cipher {
+	if !container.IsRegister[Cipher]() {
+		return nil
+	}
+	return container.Resolve[Cipher]()
+}
+
+// IsEncrypted 是否为加密后的参数
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// MaskData 参数脱敏：加密后的参数、names中的参数显示为Mask，返回新的参数
+func MaskData(data collections.Dictionary[string, string], names ...string) collections.Dictionary[string, string] {
+	if data.IsNil() {
+		return data
+	}
+	secretNames := make(map[string]bool, len(names))
+	for _, name := range names {
+		secretNames[name] = true
+	}
+	values := make(map[string]string)
+	for key, value := range data.ToMap() {
+		if value != "" && (secretNames[key] || IsEncrypted(value)) {
+			value = Mask
+		}
+		values[key] = value
+	}
+	return collections.NewDictionaryFromMap(values)
+}
+
+// MaskText 文本中加密后的参数脱敏（如审计记录中的json）
+func MaskText(text string) string {
+	return cipherRegexp.ReplaceAllString(text, Mask)
+}
@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"github.com/farseer-go/collections"
+	"testing"
+)
+
+func TestMaskData(t *testing.T) {
+	data := collections.NewDictionaryFromMap(map[string]string{
+		"password": "plain",
+		"token":    Prefix + "k1:YWJj",
+		"empty":    "",
+		"date":     "2024-01-01",
+	})
+	masked := MaskData(data, "password", "empty")
+	expect := map[string]string{"password": Mask, "token": Mask, "empty": "", "date": "2024-01-01"}
+	for key, value := range expect {
+		if masked.GetValue(key) != value {
+			t.Errorf("参数：%s 期望：%s，实际：%s", key, value, masked.GetValue(key))
+		}
+	}
+	// 不修改原参数
+	if data.GetValue("password") != "plain" {
+		t.Fatal("脱敏不应修改原参数")
+	}
+}
+
+func TestMaskText(t *testing.T) {
+	text := `{"Data":{"token":"enc:k1:YWJj+/=","date":"2024-01-01"}}`
+	if masked := MaskText(text); masked != `{"Data":{"token":"******","date":"2024-01-01"}}` {
+		t.Fatalf("脱敏结果：%s", masked)
+	}
+}
@@ -1,6 +1,7 @@
 package serverNode
 
 import (
+	"context"
 	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/configure"
 	"github.com/farseer-go/fs/parse"
@@ -10,6 +11,9 @@ import (
 
 var IsLeaderNode bool
 
+// LeaderContext 当前节点作为Master期间有效，失去Master后取消
+var LeaderContext = context.Background()
+
 type DomainObject struct {
 	Id         int64     // 客户端ID
 	Name       string    // 客户端名称
@@ -0,0 +1,34 @@
+package taskEvent
+
+import (
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/snowflake"
+	"time"
+)
+
+// DomainObject 任务的状态变更记录
+type DomainObject struct {
+	Id         int64           // 主键
+	Name       string          // 任务组名称
+	TaskId     int64           // 任务ID
+	FromStatus enum.TaskStatus // 变更前的状态
+	ToStatus   enum.TaskStatus // 变更后的状态
+	NodeId     int64           // 变更的服务端节点
+	Reason     string          // 变更原因
+	CreateAt   time.Time       // 变更时间
+}
+
+// New 创建状态变更记录
+func New(name string, taskId int64, fromStatus enum.TaskStatus, toStatus enum.TaskStatus, reason string) DomainObject {
+	return DomainObject{
+		Id:         snowflake.GenerateId(),
+		Name:       name,
+		TaskId:     taskId,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		NodeId:     fs.AppId,
+		Reason:     reason,
+		CreateAt:   time.Now(),
+	}
+}
@@ -0,0 +1,14 @@
+package taskEvent
+
+import "github.com/farseer-go/collections"
+
+type Repository interface {
+	// Add 添加状态变更记录
+	Add(do DomainObject)
+	// ToList 任务的状态变更记录（按时间顺序）
+	ToList(taskId int64) collections.List[DomainObject]
+	// RemoveByTaskIds 删除任务的状态变更记录
+	RemoveByTaskIds(taskIds []int64)
+	// Clear 清除任务组中早于taskId的任务、1天前的状态变更记录（与ClearFinish一致）
+	Clear(name string, taskId int64)
+}
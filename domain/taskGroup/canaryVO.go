@@ -0,0 +1,54 @@
+package taskGroup
+
+// CanaryVO 新版本的金丝雀发布
+type CanaryVO struct {
+	IsEnable      bool     // 客户端注册新版本时，是否进入金丝雀发布
+	Ver           int      // 金丝雀发布中的版本（0：未发布）
+	Percent       int      // 调度给新版本的百分比（未设置Tags时，Canary策略也按此百分比调度当前版本）
+	Tags          []string // 只调度给带有这些标签的新版本客户端
+	PromoteCount  int      // 连续成功多少次后，自动全量发布（0：不自动）
+	RollbackCount int      // 连续失败多少次后，自动回滚到上一个版本（0：不自动）
+	SuccessCount  int      // 新版本连续成功次数
+	FailCount     int      // 新版本连续失败次数
+}
+
+// Start 开始金丝雀发布
+func (receiver *CanaryVO) Start(ver int) {
+	receiver.Ver = ver
+	receiver.SuccessCount = 0
+	receiver.FailCount = 0
+}
+
+// Stop 结束金丝雀发布
+func (receiver *CanaryVO) Stop() {
+	receiver.Ver = 0
+}
+
+// HasTag 客户端是否带有金丝雀标签
+func (receiver *CanaryVO) HasTag(tags []string) bool {
+	for _, canaryTag := range receiver.Tags {
+		for _, tag := range tags {
+			if canaryTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Report 记录新版本的执行结果，返回是否需要全量发布、回滚
+func (receiver *CanaryVO) Report(success bool) (promote bool, rollback bool) {
+	if success {
+		receiver.SuccessCount++
+		receiver.FailCount = 0
+		promote = receiver.PromoteCount > 0 && receiver.SuccessCount >= receiver.PromoteCount
+	} else {
+		receiver.FailCount++
+		receiver.SuccessCount = 0
+		rollback = receiver.RollbackCount > 0 && receiver.FailCount >= receiver.RollbackCount
+	}
+	if promote || rollback {
+		receiver.Stop()
+	}
+	return promote, rollback
+}
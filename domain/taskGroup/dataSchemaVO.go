@@ -0,0 +1,263 @@
+package taskGroup
+
+import (
+	"FSchedule/domain/secret"
+	"fmt"
+	"github.com/farseer-go/collections"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DataSchemaVO 任务参数（Data）的定义，客户端注册时以JSON Schema上报
+type DataSchemaVO struct {
+	Properties           map[string]DataPropertyVO `json:"properties,omitempty"`           // 参数定义
+	Required             []string                  `json:"required,omitempty"`             // 必填的参数
+	AdditionalProperties *bool                     `json:"additionalProperties,omitempty"` // 是否允许未定义的参数（默认允许）
+}
+
+// DataPropertyVO 单个参数的定义
+type DataPropertyVO struct {
+	Type        string   `json:"type,omitempty"`        // 类型：string、integer、number、boolean（默认string）
+	Title       string   `json:"title,omitempty"`       // 标题
+	Description string   `json:"description,omitempty"` // 说明
+	Default     any      `json:"default,omitempty"`     // 默认值
+	Enum        []any    `json:"enum,omitempty"`        // 可选值
+	Minimum     *float64 `json:"minimum,omitempty"`     // 最小值（integer、number）
+	Maximum     *float64 `json:"maximum,omitempty"`     // 最大值（integer、number）
+	MinLength   *int     `json:"minLength,omitempty"`   // 最小长度（string）
+	MaxLength   *int     `json:"maxLength,omitempty"`   // 最大长度（string）
+	Pattern     string   `json:"pattern,omitempty"`     // 正则（string）
+	Format      string   `json:"format,omitempty"`      // 格式（textarea：多行文本、secret：加密保存并脱敏显示）
+}
+
+// FormFieldVO 管理端渲染表单的字段
+type FormFieldVO struct {
+	Name        string   // 参数名称
+	Title       string   // 标题
+	Description string   // 说明
+	Type        string   // 类型
+	Widget      string   // 控件：input、textarea、password、number、switch、select
+	Required    bool     // 是否必填
+	Default     string   // 默认值
+	Options     []string // 可选值（select）
+	Minimum     *float64 // 最小值
+	Maximum     *float64 // 最大值
+	MaxLength   *int     // 最大长度
+	Pattern     string   // 正则
+}
+
+// IsEmpty 未定义
+func (receiver *DataSchemaVO) IsEmpty() bool {
+	return len(receiver.Properties) == 0
+}
+
+// Check 检查定义是否正确
+func (receiver *DataSchemaVO) Check() error {
+	for name, property := range receiver.Properties {
+		switch property.Type {
+		case "", "string", "integer", "number", "boolean":
+		default:
+			return fmt.Errorf("参数：%s 不支持的类型：%s", name, property.Type)
+		}
+		if property.IsSecret() && property.Type != "" && property.Type != "string" {
+			return fmt.Errorf("参数：%s Secret参数的类型只能是string", name)
+		}
+		if property.Pattern != "" {
+			if _, err := regexp.Compile(property.Pattern); err != nil {
+				return fmt.Errorf("参数：%s 正则格式错误：%s", name, property.Pattern)
+			}
+		}
+	}
+	for _, name := range receiver.Required {
+		if _, exists := receiver.Properties[name]; !exists {
+			return fmt.Errorf("必填的参数：%s 未定义", name)
+		}
+	}
+	return nil
+}
+
+// Validate 校验参数（未定义时不校验）
+func (receiver *DataSchemaVO) Validate(data collections.Dictionary[string, string]) error {
+	if receiver.IsEmpty() {
+		return nil
+	}
+	values := make(map[string]string)
+	if !data.IsNil() {
+		values = data.ToMap()
+	}
+	for _, name := range receiver.Required {
+		if values[name] == "" {
+			return fmt.Errorf("参数：%s 必填", name)
+		}
+	}
+	for name, value := range values {
+		property, exists := receiver.Properties[name]
+		if !exists {
+			if receiver.AdditionalProperties != nil && !*receiver.AdditionalProperties {
+				return fmt.Errorf("参数：%s 未定义", name)
+			}
+			continue
+		}
+		// 已加密的Secret参数，在加密前已校验
+		if value == "" || secret.IsEncrypted(value) {
+			continue
+		}
+		if err := property.validate(value); err != nil {
+			return fmt.Errorf("参数：%s %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+func (receiver *DataPropertyVO) validate(value string) error {
+	switch receiver.Type {
+	case "integer", "number":
+		var number float64
+		var err error
+		if receiver.Type == "integer" {
+			var n int64
+			n, err = strconv.ParseInt(value, 10, 64)
+			number = float64(n)
+		} else {
+			number, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("不是有效的%s：%s", receiver.Type, value)
+		}
+		if receiver.Minimum != nil && number < *receiver.Minimum {
+			return fmt.Errorf("不能小于%v", *receiver.Minimum)
+		}
+		if receiver.Maximum != nil && number > *receiver.Maximum {
+			return fmt.Errorf("不能大于%v", *receiver.Maximum)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("不是有效的boolean：%s", value)
+		}
+	default:
+		length := utf8.RuneCountInString(value)
+		if receiver.MinLength != nil && length < *receiver.MinLength {
+			return fmt.Errorf("长度不能小于%d", *receiver.MinLength)
+		}
+		if receiver.MaxLength != nil && length > *receiver.MaxLength {
+			return fmt.Errorf("长度不能大于%d", *receiver.MaxLength)
+		}
+		if receiver.Pattern != "" {
+			if matched, _ := regexp.MatchString(receiver.Pattern, value); !matched {
+				return fmt.Errorf("格式不正确：%s", receiver.Pattern)
+			}
+		}
+	}
+
+	if options := receiver.options(); len(options) > 0 {
+		for _, option := range options {
+			if option == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("只能是%v之一", options)
+	}
+	return nil
+}
+
+// ApplyDefault 未设置的参数使用默认值，返回新的参数
+func (receiver *DataSchemaVO) ApplyDefault(data collections.Dictionary[string, string]) collections.Dictionary[string, string] {
+	values := make(map[string]string)
+	if !data.IsNil() {
+		for name, value := range data.ToMap() {
+			values[name] = value
+		}
+	}
+	for name, property := range receiver.Properties {
+		if _, exists := values[name]; !exists && property.Default != nil {
+			values[name] = toString(property.Default)
+		}
+	}
+	return collections.NewDictionaryFromMap(values)
+}
+
+// Form 管理端渲染表单的字段（按名称排序）
+func (receiver *DataSchemaVO) Form() []FormFieldVO {
+	required := make(map[string]bool)
+	for _, name := range receiver.Required {
+		required[name] = true
+	}
+	fields := make([]FormFieldVO, 0, len(receiver.Properties))
+	for name, property := range receiver.Properties {
+		field := FormFieldVO{
+			Name:        name,
+			Title:       property.Title,
+			Description: property.Description,
+			Type:        property.Type,
+			Widget:      property.widget(),
+			Required:    required[name],
+			Options:     property.options(),
+			Minimum:     property.Minimum,
+			Maximum:     property.Maximum,
+			MaxLength:   property.MaxLength,
+			Pattern:     property.Pattern,
+		}
+		if field.Title == "" {
+			field.Title = name
+		}
+		if field.Type == "" {
+			field.Type = "string"
+		}
+		if property.Default != nil && !property.IsSecret() {
+			field.Default = toString(property.Default)
+		}
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+func (receiver *DataPropertyVO) widget() string {
+	switch {
+	case receiver.IsSecret():
+		return "password"
+	case len(receiver.Enum) > 0:
+		return "select"
+	case receiver.Type == "boolean":
+		return "switch"
+	case receiver.Type == "integer" || receiver.Type == "number":
+		return "number"
+	case receiver.Format == "textarea":
+		return "textarea"
+	}
+	return "input"
+}
+
+// IsSecret 是否为Secret参数
+func (receiver *DataPropertyVO) IsSecret() bool {
+	return receiver.Format == "secret"
+}
+
+// SecretNames Secret参数的名称
+func (receiver *DataSchemaVO) SecretNames() []string {
+	var names []string
+	for name, property := range receiver.Properties {
+		if property.IsSecret() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (receiver *DataPropertyVO) options() []string {
+	var options []string
+	for _, item := range receiver.Enum {
+		options = append(options, toString(item))
+	}
+	return options
+}
+
+// JSON中的数字为float64，避免大数转为科学计数法
+func toString(value any) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
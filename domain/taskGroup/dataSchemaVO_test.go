@@ -0,0 +1,120 @@
+package taskGroup
+
+import (
+	"encoding/json"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/container"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// 未注册Secret参数的加密方式时，参数以明文保存
+	container.InitContainer()
+	os.Exit(m.Run())
+}
+
+func newSchema(t *testing.T, content string) DataSchemaVO {
+	var schema DataSchemaVO
+	if err := json.Unmarshal([]byte(content), &schema); err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func newData(values map[string]string) collections.Dictionary[string, string] {
+	return collections.NewDictionaryFromMap(values)
+}
+
+const testSchema = `{"properties": {"date": {"type": "string", "pattern": "^\\d{4}-\\d{2}-\\d{2}$"}, "batchSize": {"type": "integer", "default": 1000, "minimum": 1, "maximum": 5000}, "mode": {"enum": ["full", "incr"]}, "dryRun": {"type": "boolean"}}, "required": ["date"], "additionalProperties": false}`
+
+func TestDataSchemaCheck(t *testing.T) {
+	schema := newSchema(t, testSchema)
+	if err := schema.Check(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []string{
+		`{"properties": {"a": {"type": "array"}}}`,
+		`{"properties": {"a": {"type": "integer", "format": "secret"}}}`,
+		`{"properties": {"a": {"pattern": "["}}}`,
+		`{"properties": {"a": {}}, "required": ["b"]}`,
+	}
+	for _, content := range invalid {
+		schema = newSchema(t, content)
+		if err := schema.Check(); err == nil {
+			t.Errorf("定义：%s 应检查不通过", content)
+		}
+	}
+}
+
+func TestDataSchemaValidate(t *testing.T) {
+	schema := newSchema(t, testSchema)
+	cases := []struct {
+		data  map[string]string
+		valid bool
+	}{
+		{map[string]string{"date": "2024-01-01"}, true},
+		{map[string]string{"date": "2024-01-01", "batchSize": "200", "mode": "incr", "dryRun": "true"}, true},
+		{map[string]string{}, false},
+		{map[string]string{"date": "20240101"}, false},
+		{map[string]string{"date": "2024-01-01", "batchSize": "abc"}, false},
+		{map[string]string{"date": "2024-01-01", "batchSize": "0"}, false},
+		{map[string]string{"date": "2024-01-01", "batchSize": "5001"}, false},
+		{map[string]string{"date": "2024-01-01", "mode": "all"}, false},
+		{map[string]string{"date": "2024-01-01", "dryRun": "yes"}, false},
+		{map[string]string{"date": "2024-01-01", "other": "1"}, false},
+		// 已加密的Secret参数不再校验
+		{map[string]string{"date": "2024-01-01", "batchSize": "enc:k1:YWJj"}, true},
+	}
+	for _, c := range cases {
+		if err := schema.Validate(newData(c.data)); (err == nil) != c.valid {
+			t.Errorf("参数：%v 期望：%v，实际：%v", c.data, c.valid, err)
+		}
+	}
+
+	// 未定义时不校验
+	var empty DataSchemaVO
+	if err := empty.Validate(newData(map[string]string{"any": "value"})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataSchemaApplyDefault(t *testing.T) {
+	schema := newSchema(t, testSchema)
+	data := schema.ApplyDefault(newData(map[string]string{"date": "2024-01-01"}))
+	if data.GetValue("batchSize") != "1000" {
+		t.Fatalf("未设置的参数应使用默认值：%v", data.ToMap())
+	}
+	data = schema.ApplyDefault(newData(map[string]string{"batchSize": "10"}))
+	if data.GetValue("batchSize") != "10" {
+		t.Fatalf("已设置的参数不应使用默认值：%v", data.ToMap())
+	}
+}
+
+func TestUpdateVerStoresSchemaOfSameVersion(t *testing.T) {
+	do := DomainObject{Name: "job", Ver: 2, Data: newData(map[string]string{"date": "2024-01-01"})}
+	schema := newSchema(t, testSchema)
+
+	// 同一版本的客户端注册时，参数定义有变化也要更新
+	if err := do.UpdateVer("job", "", 2, "", 0, false, 0, 0, 0, "", schema); err != nil {
+		t.Fatal(err)
+	}
+	if !do.NeedSave || do.DataSchema.IsEmpty() || do.Data.GetValue("batchSize") != "1000" {
+		t.Fatalf("应保存新的参数定义并使用默认值：NeedSave=%v Data=%v", do.NeedSave, do.Data.ToMap())
+	}
+
+	// 参数定义没有变化时不需要保存
+	do.NeedSave = false
+	if err := do.UpdateVer("job", "", 2, "", 0, false, 0, 0, 0, "", newSchema(t, testSchema)); err != nil || do.NeedSave {
+		t.Fatalf("参数定义没有变化：NeedSave=%v err=%v", do.NeedSave, err)
+	}
+
+	// 当前参数不符合新的参数定义时返回原因，定义仍然保存
+	required := newSchema(t, `{"properties": {"date": {}, "region": {}}, "required": ["region"]}`)
+	if err := do.UpdateVer("job", "", 2, "", 0, false, 0, 0, 0, "", required); err == nil {
+		t.Fatal("缺少必填参数时应返回原因")
+	}
+	if _, exists := do.DataSchema.Properties["region"]; !exists {
+		t.Fatal("参数定义应已保存")
+	}
+}
@@ -2,50 +2,105 @@ package taskGroup
 
 import (
 	"FSchedule/domain/enum"
+	"FSchedule/domain/secret"
+	"FSchedule/domain/taskEvent"
+	"fmt"
 	"github.com/farseer-go/collections"
 	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/fs/snowflake"
 	"github.com/robfig/cron/v3"
+	"math"
+	"math/rand"
+	"reflect"
 	"time"
 )
 
 var standardParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 
+// CheckCron 检查Cron格式是否正确
+func CheckCron(strCron string) bool {
+	_, err := standardParser.Parse(strCron)
+	return err == nil
+}
+
 type DomainObject struct {
-	Name        string                                 // 实现Job的特性名称（客户端识别哪个实现类）
-	Ver         int                                    // 版本
-	Task        TaskEO                                 // 最新的任务
-	Caption     string                                 // 任务组标题
-	Data        collections.Dictionary[string, string] // 本次执行任务时的Data数据
-	StartAt     time.Time                              // 开始时间
-	NextAt      time.Time                              // 下次执行时间
-	Cron        string                                 // 时间定时器表达式
-	ActivateAt  time.Time                              // 活动时间
-	LastRunAt   time.Time                              // 最后一次完成时间
-	IsEnable    bool                                   // 是否开启
-	RunSpeedAvg int64                                  // 运行平均耗时
-	RunCount    int                                    // 运行次数
-	NeedSave    bool                                   // 是否需要保存
-}
-
-// UpdateVer 更新新的版本
-func (receiver *DomainObject) UpdateVer(name string, caption string, ver int, strCron string, StartAt int64, enable bool) {
+	Name              string                                 // 实现Job的特性名称（客户端识别哪个实现类）
+	Ver               int                                    // 版本
+	Task              TaskEO                                 // 最新的任务
+	Caption           string                                 // 任务组标题
+	Data              collections.Dictionary[string, string] // 本次执行任务时的Data数据
+	DataSchema        DataSchemaVO                           // 任务参数（Data）的定义，客户端注册时上报
+	StartAt           time.Time                              // 开始时间
+	NextAt            time.Time                              // 下次执行时间
+	Cron              string                                 // 时间定时器表达式
+	ActivateAt        time.Time                              // 活动时间
+	LastRunAt         time.Time                              // 最后一次完成时间
+	IsEnable          bool                                   // 是否开启
+	RunSpeedAvg       int64                                  // 运行平均耗时
+	RunCount          int                                    // 运行次数
+	NeedSave          bool                                   // 是否需要保存
+	ConcurrencyPolicy enum.ConcurrencyPolicy                 // 任务执行中到达下一个周期时的处理策略
+	MaxParallel       int                                    // 最多同时执行的任务数量（Allow策略，0不限制）
+	RunningTaskIds    []int64                                // 并行执行中的任务（不包含当前任务）
+	TickAt            time.Time                              // 任务执行中，最后一次到达的执行周期
+	SkipCount         int                                    // 当前任务执行中，跳过的执行周期数量
+	SkipAt            time.Time                              // 当前任务执行中，第一次跳过的执行周期
+	SkipTaskId        int64                                  // 当前任务执行中，跳过的执行周期合并记录的任务ID
+	IsClientNextAt    bool                                   // 下次执行时间由客户端决定（不按Cron计算执行周期）
+	Priority          int                                    // 优先级（排队时，数值越大越先调度）
+	Namespace         string                                 // 命名空间（按命名空间限制同时执行的任务数量）
+	RollbackVer       int                                    // 回滚到的版本，优先调度给该版本的客户端（0：未回滚）
+	VersionPolicy     enum.VersionPolicy                     // 调度客户端版本的策略
+	AllowPrevious     int                                    // 允许调度给前N个版本的客户端（AllowPrevious策略）
+	Canary            CanaryVO                               // 新版本的金丝雀发布
+	LogRetention      LogRetentionVO                         // 任务日志的保留策略（未设置时使用全局配置）
+	Checkpoint        string                                 // 客户端下线时任务保存的断点，交给下一个任务续跑
+	IsImported        bool                                   // 定义由配置导入管理（客户端注册时只更新版本、参数定义）
+	events            []taskEvent.DomainObject               // 已被替换的任务、跳过的周期中未发布的状态变更事件
+}
+
+// UpdateVer 更新新的版本，返回当前参数不符合客户端注册的参数定义的原因
+func (receiver *DomainObject) UpdateVer(name string, caption string, ver int, strCron string, StartAt int64, enable bool, policy enum.ConcurrencyPolicy, maxParallel int, priority int, namespace string, dataSchema DataSchemaVO) error {
+	var schemaErr error
+	// 同一版本的客户端注册时，参数定义有变化也要更新
+	if receiver.Ver == ver && !reflect.DeepEqual(receiver.DataSchema, dataSchema) {
+		schemaErr = receiver.applySchema(dataSchema)
+		receiver.NeedSave = true
+	}
+
 	// 只更新高一个版本号的数据
 	if receiver.Ver+1 == ver {
 		receiver.Name = name
-		receiver.Caption = caption
 		receiver.Ver = ver
-		receiver.Cron = strCron
-		receiver.StartAt = time.Unix(StartAt, 0)
 		receiver.NeedSave = true
-		receiver.IsEnable = enable
+		// 由配置导入管理的任务组，保持导入的定义
+		if !receiver.IsImported {
+			receiver.Caption = caption
+			receiver.Cron = strCron
+			receiver.StartAt = time.Unix(StartAt, 0)
+			receiver.IsEnable = enable
+			receiver.ConcurrencyPolicy = policy
+			receiver.MaxParallel = maxParallel
+			receiver.Priority = priority
+			receiver.Namespace = namespace
+		}
+		enable = receiver.IsEnable
+		receiver.RollbackVer = 0
+		schemaErr = receiver.applySchema(dataSchema)
+
+		// 新版本先进入金丝雀发布
+		if receiver.Canary.IsEnable && ver > 1 {
+			receiver.Canary.Start(ver)
+		} else {
+			receiver.Canary.Stop()
+		}
 
 		if enable {
 			cornSchedule, err := standardParser.Parse(receiver.Cron)
 			if err != nil {
 				_ = flog.Errorf("Name:%s，Cron格式错误:%s", receiver.Name, receiver.Cron)
 				receiver.NeedSave = false
-				return
+				return schemaErr
 			} else {
 				receiver.NextAt = cornSchedule.Next(time.Now())
 				receiver.ActivateAt = time.Now()
@@ -58,6 +113,196 @@ func (receiver *DomainObject) UpdateVer(name string, caption string, ver int, st
 		receiver.CreateTask()
 		receiver.NeedSave = true
 	}
+	return schemaErr
+}
+
+// 更新参数定义，未设置的参数使用默认值，返回当前参数不符合新定义的原因
+func (receiver *DomainObject) applySchema(dataSchema DataSchemaVO) error {
+	receiver.DataSchema = dataSchema
+	receiver.Data = dataSchema.ApplyDefault(receiver.Data)
+	receiver.ProtectSecret()
+	// 未开始的任务使用新的参数
+	if receiver.Task.Status == enum.None {
+		receiver.Task.Data = receiver.Data
+	}
+	return dataSchema.Validate(receiver.Data)
+}
+
+// ToVersion 当前版本的定义
+func (receiver *DomainObject) ToVersion() VersionEO {
+	return VersionEO{
+		Name:              receiver.Name,
+		Ver:               receiver.Ver,
+		Caption:           receiver.Caption,
+		Cron:              receiver.Cron,
+		StartAt:           receiver.StartAt,
+		IsEnable:          receiver.IsEnable,
+		Data:              receiver.Data,
+		DataSchema:        receiver.DataSchema,
+		ConcurrencyPolicy: receiver.ConcurrencyPolicy,
+		MaxParallel:       receiver.MaxParallel,
+		Priority:          receiver.Priority,
+		Namespace:         receiver.Namespace,
+		CreateAt:          time.Now(),
+	}
+}
+
+// Rollback 回滚到历史版本的定义（Ver保持不变，客户端注册Ver+1时才会更新）
+func (receiver *DomainObject) Rollback(version VersionEO) {
+	receiver.RollbackVer = version.Ver
+	if receiver.RollbackVer == receiver.Ver {
+		receiver.RollbackVer = 0
+	}
+	receiver.DataSchema = version.DataSchema
+	receiver.ApplyDefinition(version)
+}
+
+// ApplyDefinition 更新任务组的定义，运行数据（RunCount、RunSpeedAvg等）保持不变
+func (receiver *DomainObject) ApplyDefinition(version VersionEO) {
+	if receiver.Cron != version.Cron {
+		receiver.NextAt = time.Time{}
+	}
+	receiver.Caption = version.Caption
+	receiver.Cron = version.Cron
+	receiver.StartAt = version.StartAt
+	receiver.Data = receiver.ProtectData(receiver.restoreMasked(version.Data))
+	receiver.ConcurrencyPolicy = version.ConcurrencyPolicy
+	receiver.MaxParallel = version.MaxParallel
+	receiver.Priority = version.Priority
+	receiver.Namespace = version.Namespace
+	receiver.SetEnable(version.IsEnable)
+}
+
+// DispatchVer 调度的客户端版本
+func (receiver *DomainObject) DispatchVer() int {
+	if receiver.RollbackVer > 0 {
+		return receiver.RollbackVer
+	}
+	return receiver.Ver
+}
+
+// SetVersionPolicy 设置调度客户端版本的策略
+func (receiver *DomainObject) SetVersionPolicy(policy enum.VersionPolicy, allowPrevious int) {
+	receiver.VersionPolicy = policy
+	receiver.AllowPrevious = allowPrevious
+}
+
+// SetLogRetention 设置任务日志的保留策略
+func (receiver *DomainObject) SetLogRetention(retention LogRetentionVO) {
+	receiver.LogRetention = retention
+}
+
+// GetLogRetention 实际生效的日志保留策略
+func (receiver *DomainObject) GetLogRetention() LogRetentionVO {
+	if receiver.LogRetention.IsEmpty() {
+		return DefaultLogRetention()
+	}
+	return receiver.LogRetention
+}
+
+// DispatchVersions 按优先顺序返回可调度的客户端版本
+func (receiver *DomainObject) DispatchVersions() []int {
+	var versions []int
+	for v := receiver.DispatchVer(); v >= receiver.minDispatchVer(); v-- {
+		versions = append(versions, v)
+	}
+
+	// 未命中金丝雀比例时，当前版本放到最后（旧版本客户端都不可用时才调度）
+	if len(versions) > 1 && receiver.missCanary() {
+		versions = append(versions[1:], versions[0])
+	}
+	return versions
+}
+
+// 是否未命中金丝雀比例
+func (receiver *DomainObject) missCanary() bool {
+	// 金丝雀发布中，指定了标签时，由标签决定客户端
+	if receiver.IsCanary() {
+		return len(receiver.Canary.Tags) == 0 && rand.Intn(100) >= receiver.Canary.Percent
+	}
+	return receiver.VersionPolicy == enum.Canary && rand.Intn(100) >= receiver.Canary.Percent
+}
+
+// IsCanary 当前版本是否处于金丝雀发布中
+func (receiver *DomainObject) IsCanary() bool {
+	return receiver.Canary.Ver > 0 && receiver.Canary.Ver == receiver.Ver && receiver.RollbackVer == 0
+}
+
+// IsCanaryClient 金丝雀发布中，指定了标签时，新版本只调度给带有标签的客户端
+func (receiver *DomainObject) IsCanaryClient(ver int, tags []string) bool {
+	if !receiver.IsCanary() || ver != receiver.Canary.Ver || len(receiver.Canary.Tags) == 0 {
+		return true
+	}
+	return receiver.Canary.HasTag(tags)
+}
+
+// SetCanary 设置金丝雀发布，start=true时，当前版本立即进入金丝雀发布
+func (receiver *DomainObject) SetCanary(canary CanaryVO, start bool) {
+	canary.Ver = receiver.Canary.Ver
+	canary.SuccessCount = receiver.Canary.SuccessCount
+	canary.FailCount = receiver.Canary.FailCount
+	receiver.Canary = canary
+	if start && receiver.Ver > 1 {
+		receiver.Canary.Start(receiver.Ver)
+	}
+	if !canary.IsEnable {
+		receiver.Canary.Stop()
+	}
+}
+
+// CanaryReport 任务完成时，记录新版本的执行结果，返回是否需要全量发布、回滚
+func (receiver *DomainObject) CanaryReport() (promote bool, rollback bool) {
+	if !receiver.IsCanary() || receiver.Task.Ver != receiver.Canary.Ver {
+		return false, false
+	}
+	switch receiver.Task.Status {
+	case enum.Success:
+		return receiver.Canary.Report(true)
+	case enum.Fail:
+		return receiver.Canary.Report(false)
+	}
+	return false, false
+}
+
+// SetClientVer 记录执行任务的客户端版本
+func (receiver *DomainObject) SetClientVer(ver int) {
+	if ver > 0 {
+		receiver.Task.Ver = ver
+	}
+}
+
+// AllowVer 是否允许调度给该版本的客户端
+func (receiver *DomainObject) AllowVer(ver int) bool {
+	return ver <= receiver.DispatchVer() && ver >= receiver.minDispatchVer()
+}
+
+// 允许调度的最低客户端版本
+func (receiver *DomainObject) minDispatchVer() int {
+	switch receiver.VersionPolicy {
+	case enum.Strict:
+		return receiver.DispatchVer()
+	case enum.AllowPrevious:
+		if minVer := receiver.DispatchVer() - receiver.AllowPrevious; minVer > 1 {
+			return minVer
+		}
+	}
+	return 1
+}
+
+// ReleaseImport 解除导入管理，客户端注册时重新更新定义
+func (receiver *DomainObject) ReleaseImport() {
+	receiver.IsImported = false
+}
+
+// SetEnable 开启、停止任务组
+func (receiver *DomainObject) SetEnable(enable bool) {
+	receiver.IsEnable = enable
+	if enable {
+		receiver.CalculateNextAtByCron()
+		if receiver.Task.IsNull() {
+			receiver.CreateTask()
+		}
+	}
 }
 
 // CreateTask 创建新的Task
@@ -67,12 +312,13 @@ func (receiver *DomainObject) CreateTask() {
 		receiver.LastRunAt = time.Now()
 		receiver.ActivateAt = time.Now()
 	}
+	receiver.events = append(receiver.events, receiver.Task.PopEvents()...)
 	receiver.Task = TaskEO{
 		Id:          snowflake.GenerateId(),
-		Ver:         receiver.Ver,
+		Ver:         receiver.DispatchVer(),
 		Caption:     receiver.Caption,
 		Name:        receiver.Name,
-		StartAt:     receiver.NextAt,
+		StartAt:     receiver.nextStartAt(),
 		RunAt:       time.Now(),
 		RunSpeed:    0,
 		Progress:    0,
@@ -80,15 +326,153 @@ func (receiver *DomainObject) CreateTask() {
 		CreateAt:    time.Now(),
 		SchedulerAt: time.Now(),
 		Data:        receiver.Data,
+		Checkpoint:  receiver.Checkpoint,
 	}
+	receiver.Checkpoint = ""
+	// 新任务重新开始统计跳过的执行周期
+	receiver.SkipCount = 0
+	receiver.SkipAt = time.Time{}
+	receiver.SkipTaskId = 0
 }
 
-// SetClient 分配客户端
-func (receiver *DomainObject) SetClient(client ClientVO) {
-	receiver.Task.Client = client
-	receiver.Task.Status = enum.Working
-	receiver.Task.SchedulerAt = time.Now()
+// 新任务的开始时间：有待续跑的断点时（故障转移）立即开始，不等下一个执行周期
+func (receiver *DomainObject) nextStartAt() time.Time {
+	if receiver.Checkpoint != "" && receiver.NextAt.After(time.Now()) {
+		return time.Now()
+	}
+	return receiver.NextAt
+}
+
+// NextTickAt 任务执行中，下一个执行周期的时间
+func (receiver *DomainObject) NextTickAt() time.Time {
+	// 由客户端决定下次执行时间的，没有执行周期；默认策略等任务完成后再计算下一个周期
+	if receiver.IsClientNextAt || receiver.ConcurrencyPolicy == enum.Delay {
+		return time.Time{}
+	}
+	cornSchedule, err := standardParser.Parse(receiver.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	tickAt := receiver.Task.StartAt
+	if receiver.TickAt.After(tickAt) {
+		tickAt = receiver.TickAt
+	}
+	return cornSchedule.Next(tickAt)
+}
+
+// Tick 任务执行中，到达了下一个执行周期
+func (receiver *DomainObject) Tick(tickAt time.Time) {
+	receiver.TickAt = tickAt
+}
+
+// Skip 跳过本次执行周期，返回跳过的任务记录（连续跳过的周期合并为同一条记录）
+func (receiver *DomainObject) Skip() TaskEO {
+	if receiver.SkipCount == 0 {
+		receiver.SkipAt = receiver.TickAt
+		receiver.SkipTaskId = snowflake.GenerateId()
+	}
+	receiver.SkipCount++
+	message := fmt.Sprintf("上一次任务仍在执行，跳过%d个执行周期（%s ~ %s）", receiver.SkipCount, receiver.SkipAt.Format(time.DateTime), receiver.TickAt.Format(time.DateTime))
+	skipTask := TaskEO{
+		Id:          receiver.SkipTaskId,
+		Ver:         receiver.DispatchVer(),
+		Caption:     receiver.Caption,
+		Name:        receiver.Name,
+		StartAt:     receiver.SkipAt,
+		RunAt:       time.Now(),
+		Status:      enum.Skip,
+		CreateAt:    time.Now(),
+		SchedulerAt: time.Now(),
+		Data:        receiver.Data,
+		Message:     message,
+	}
+	// 只在第一次跳过时产生状态变更事件，之后只更新记录
+	if receiver.SkipCount == 1 {
+		receiver.events = append(receiver.events, taskEvent.New(skipTask.Name, skipTask.Id, enum.None, enum.Skip, message))
+	}
+	return skipTask
+}
+
+// CanParallel 是否允许再并行执行一个任务
+func (receiver *DomainObject) CanParallel() bool {
+	return receiver.ConcurrencyPolicy == enum.Allow && (receiver.MaxParallel <= 0 || len(receiver.RunningTaskIds)+1 < receiver.MaxParallel)
+}
+
+// Parallel 当前任务转入后台执行，并为本次周期创建新的任务
+func (receiver *DomainObject) Parallel() {
+	receiver.RunningTaskIds = append(receiver.RunningTaskIds, receiver.Task.Id)
+	receiver.NextAt = receiver.TickAt
+	receiver.CreateTask()
+}
+
+// Replace 终止当前任务，本次周期重新开始（不允许的状态变更返回false）
+func (receiver *DomainObject) Replace() bool {
+	if !receiver.Task.SetFail("到达下一个执行周期，终止当前任务") {
+		return false
+	}
+	// 重新开始的任务从断点续跑
+	receiver.Checkpoint = receiver.Task.Checkpoint
+	receiver.NextAt = receiver.TickAt
+	return true
+}
+
+// RunningTaskOffline 并行执行中的任务，客户端下线了，设为失败，下一个任务从断点续跑（不允许的状态变更返回false）
+func (receiver *DomainObject) RunningTaskOffline(taskEO *TaskEO) bool {
+	if !taskEO.SetFail("客户端下线") {
+		return false
+	}
+	if taskEO.Checkpoint != "" {
+		receiver.Checkpoint = taskEO.Checkpoint
+	}
+	return true
+}
+
+// IsRunningTask 是否为并行执行中的任务
+func (receiver *DomainObject) IsRunningTask(taskId int64) bool {
+	for _, id := range receiver.RunningTaskIds {
+		if id == taskId {
+			return true
+		}
+	}
+	return false
+}
+
+// RunningTaskFinish 并行执行的任务完成后，从列表中移除
+func (receiver *DomainObject) RunningTaskFinish(taskEO TaskEO) bool {
+	if !taskEO.IsFinish() {
+		return false
+	}
+	for i, taskId := range receiver.RunningTaskIds {
+		if taskId == taskEO.Id {
+			receiver.RunningTaskIds = append(receiver.RunningTaskIds[:i:i], receiver.RunningTaskIds[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetClient 分配客户端（不允许的状态变更返回false）
+func (receiver *DomainObject) SetClient(client ClientVO) bool {
+	if !receiver.Task.SetClient(client) {
+		return false
+	}
 	receiver.Task.RunAt = time.Now()
+	return true
+}
+
+// Lease 客户端拉取任务（不允许的状态变更返回false）
+func (receiver *DomainObject) Lease(client ClientVO, leaseTime time.Duration) bool {
+	return receiver.Task.Lease(client, leaseTime)
+}
+
+// RenewLease 续约
+func (receiver *DomainObject) RenewLease(leaseTime time.Duration) {
+	receiver.Task.RenewLease(leaseTime)
+}
+
+// CanPull 任务是否可以被客户端拉取
+func (receiver *DomainObject) CanPull() bool {
+	return receiver.IsEnable && receiver.Task.Status == enum.Scheduling && !time.Now().Before(receiver.Task.StartAt)
 }
 
 // IsNil 不存在
@@ -104,14 +488,75 @@ func (receiver *DomainObject) UpdateTask(taskEO TaskEO) {
 	}
 }
 
-// ScheduleFail 调度失败
-func (receiver *DomainObject) ScheduleFail() {
-	receiver.Task.ScheduleFail()
+// ScheduleFail 调度失败（不允许的状态变更返回false）
+func (receiver *DomainObject) ScheduleFail(reason string) bool {
+	return receiver.Task.ScheduleFail(reason)
+}
+
+// Trigger 立即执行一次（任务未开始时，把开始时间提前到现在）
+func (receiver *DomainObject) Trigger(override map[string]string) bool {
+	if receiver.Task.IsWorking() || receiver.Task.Status == enum.Scheduling {
+		return false
+	}
+	receiver.NextAt = time.Now()
+	receiver.CreateTask()
+	// 只覆盖本次执行的参数，完成后不同步到任务组
+	if len(override) > 0 {
+		receiver.Task.Data = receiver.ProtectData(receiver.MergeData(override))
+		receiver.Task.IsOverride = true
+	}
+	return true
+}
+
+// MergeData 在任务组的参数上覆盖部分参数，返回新的参数
+func (receiver *DomainObject) MergeData(override map[string]string) collections.Dictionary[string, string] {
+	data := make(map[string]string)
+	if !receiver.Data.IsNil() {
+		for key, value := range receiver.Data.ToMap() {
+			data[key] = value
+		}
+	}
+	for key, value := range override {
+		// 脱敏的Secret参数（未修改）使用当前的值
+		if value != secret.Mask {
+			data[key] = value
+		}
+	}
+	return collections.NewDictionaryFromMap(data)
 }
 
-// ClientOffline 客户端下线了
-func (receiver *DomainObject) ClientOffline() {
-	receiver.Task.SetFail()
+// ValidateData 按参数定义校验（脱敏的Secret参数使用当前的值）
+func (receiver *DomainObject) ValidateData(data collections.Dictionary[string, string]) error {
+	return receiver.DataSchema.Validate(receiver.restoreMasked(data))
+}
+
+// SetData 修改传给客户端的参数（不符合参数定义时返回错误）
+func (receiver *DomainObject) SetData(data collections.Dictionary[string, string]) error {
+	data = receiver.restoreMasked(data)
+	if err := receiver.DataSchema.Validate(data); err != nil {
+		return err
+	}
+	receiver.Data = receiver.ProtectData(data)
+	// 未开始的任务使用新的参数
+	if receiver.Task.Status == enum.None {
+		receiver.Task.Data = receiver.Data
+	}
+	return nil
+}
+
+// Kill 终止执行中的任务（不允许的状态变更返回false）
+func (receiver *DomainObject) Kill() bool {
+	return receiver.Task.SetFail("终止任务")
+}
+
+// ClientOffline 客户端下线了（不允许的状态变更返回false）
+func (receiver *DomainObject) ClientOffline() bool {
+	if !receiver.Task.SetFail("客户端下线") {
+		return false
+	}
+	// 下一个任务从断点续跑
+	receiver.Checkpoint = receiver.Task.Checkpoint
+	return true
 }
 
 // CanScheduler 是否可以调度
@@ -128,6 +573,7 @@ func (receiver *DomainObject) CanScheduler() bool {
 func (receiver *DomainObject) CalculateNextAtByUnix(timespan int64) {
 	if timespan > 0 {
 		receiver.NextAt = time.UnixMilli(timespan)
+		receiver.IsClientNextAt = true
 	}
 }
 
@@ -139,29 +585,54 @@ func (receiver *DomainObject) CalculateNextAtByCron() {
 			_ = flog.Errorf("Name:%s，Cron格式错误:%s", receiver.Name, receiver.Cron)
 		}
 		receiver.NextAt = cornSchedule.Next(time.Now())
+		receiver.IsClientNextAt = false
 	}
 }
 
+// Weight 调度负载的权重：每小时的执行次数（按Cron计算，无法计算时按已运行次数估算）
+func (receiver *DomainObject) Weight() int64 {
+	if !receiver.IsEnable {
+		return 1
+	}
+	if cornSchedule, err := standardParser.Parse(receiver.Cron); err == nil {
+		nextAt := cornSchedule.Next(time.Now())
+		if interval := cornSchedule.Next(nextAt).Sub(nextAt); interval > 0 && interval < time.Hour {
+			return int64(time.Hour / interval)
+		}
+		return 1
+	}
+	// 由客户端决定下次执行时间的，运行次数越多，权重越大
+	if receiver.RunCount > 0 {
+		return int64(math.Log10(float64(receiver.RunCount))) + 1
+	}
+	return 1
+}
+
 // SyncData 同步Data
 func (receiver *DomainObject) SyncData() {
-	if receiver.Task.Status == enum.Success {
+	if receiver.Task.Status == enum.Success && !receiver.Task.IsOverride {
 		receiver.Data = receiver.Task.Data
 	}
 }
 
-// Report 任务报告
-func (receiver *DomainObject) Report(status enum.TaskStatus, data collections.Dictionary[string, string], progress int, runSpeed int64, nextTimespan int64, taskGroupRepository Repository) {
+// Report 任务报告（不允许的状态变更返回false），由调用方保存
+func (receiver *DomainObject) Report(status enum.TaskStatus, data collections.Dictionary[string, string], progress int, runSpeed int64, nextTimespan int64) bool {
+	if !receiver.Task.UpdateTask(status, data, progress, runSpeed) {
+		return false
+	}
 	receiver.ActivateAt = time.Now()
 	receiver.LastRunAt = time.Now()
-	receiver.Task.UpdateTask(status, data, progress, runSpeed)
 	receiver.SyncData()
+	// 客户端上报的参数中包含明文的Secret参数
+	receiver.ProtectSecret()
 	// 客户端动态计算下一个执行周期
 	receiver.CalculateNextAtByUnix(nextTimespan)
-	taskGroupRepository.Save(*receiver)
+	return true
+}
 
-	//if receiver.Task.IsFinish() {
-	//	_ = container.Resolve[core.IEvent]("TaskFinish").Publish(receiver)
-	//} else {
-	//	taskGroupRepository.Save(*receiver)
-	//}
+// PopEvents 取出未发布的状态变更事件（包括当前任务的），保存成功后由应用层发布
+func (receiver *DomainObject) PopEvents() []taskEvent.DomainObject {
+	events := append(receiver.events, receiver.Task.PopEvents()...)
+	receiver.events = nil
+	return events
 }
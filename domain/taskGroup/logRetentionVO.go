@@ -0,0 +1,40 @@
+package taskGroup
+
+import (
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
+// LogRetentionVO 任务日志的保留策略
+type LogRetentionVO struct {
+	Days          int              // 日志保留天数（0：不清除）
+	KeepLevel     eumLogLevel.Enum // 达到该级别的日志，按KeepLevelDays保留
+	KeepLevelDays int              // 达到KeepLevel的日志保留天数（0：与Days相同）
+}
+
+// DefaultLogRetention 全局的日志保留策略（任务组未设置时使用）
+func DefaultLogRetention() LogRetentionVO {
+	return LogRetentionVO{
+		Days:          configure.GetInt("FSchedule.LogRetention.Days"),
+		KeepLevel:     eumLogLevel.Enum(configure.GetInt("FSchedule.LogRetention.KeepLevel")),
+		KeepLevelDays: configure.GetInt("FSchedule.LogRetention.KeepLevelDays"),
+	}
+}
+
+// IsEmpty 未设置
+func (receiver *LogRetentionVO) IsEmpty() bool {
+	return receiver.Days == 0 && receiver.KeepLevelDays == 0
+}
+
+// ClearBefore 该级别的日志，早于返回时间的需要清除（零值表示不清除）
+func (receiver *LogRetentionVO) ClearBefore(level eumLogLevel.Enum) time.Time {
+	days := receiver.Days
+	if level >= receiver.KeepLevel && receiver.KeepLevelDays > 0 {
+		days = receiver.KeepLevelDays
+	}
+	if days <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+}
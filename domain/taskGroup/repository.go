@@ -1,28 +1,54 @@
 package taskGroup
 
 import (
+	"errors"
 	"github.com/farseer-go/collections"
 )
 
+// ErrFenced 当前节点已失去任务组的调度锁（已被其它节点接管），调度线程不能再写入
+var ErrFenced = errors.New("已由其它节点调度，拒绝当前节点写入")
+
 type Repository interface {
 	// ToEntity 获取任务组信息
 	ToEntity(name string) DomainObject
 	// ToList 获取所有任务组中的任务
 	ToList() collections.List[DomainObject]
+	// Add 新建任务组（同时写入数据库，并通知所有节点）
+	Add(do *DomainObject)
 	// Save 保存任务组信息
 	Save(do DomainObject)
 	// SaveAndTask 保存任务组、任务信息
 	SaveAndTask(do DomainObject)
 	// SaveTask 保存任务信息
 	SaveTask(taskEO TaskEO)
+	// SaveFenced 调度线程保存任务组信息（当前节点已失去调度锁时返回ErrFenced）
+	SaveFenced(do DomainObject) error
+	// SaveAndTaskFenced 调度线程保存任务组、任务信息（当前节点已失去调度锁时返回ErrFenced）
+	SaveAndTaskFenced(do DomainObject) error
+	// SaveTaskFenced 调度线程保存任务信息（当前节点已失去调度锁时返回ErrFenced）
+	SaveTaskFenced(taskEO TaskEO) error
 	// GetTask 获取任务信息
 	GetTask(name string, taskId int64) TaskEO
 	// ToTaskSpeedList 当前任务组下所有任务的执行速度
 	ToTaskSpeedList(name string) []int64
 	// ToFinishList 获取指定任务组执行成功的任务列表
 	ToFinishList(name string, top int) collections.List[TaskEO]
+	// GetTaskUnFinishList 获取指定任务名称中未完成的任务组
+	GetTaskUnFinishList(jobsNames []string, top int) collections.List[DomainObject]
 	// ClearFinish 清除成功的任务记录（1天前）
-	ClearFinish(name string, taskId int)
+	ClearFinish(name string, taskId int64)
+	// ToClearList 获取ClearFinish会清除的任务记录（按Id顺序，最多top条）
+	ToClearList(name string, taskId int64, top int) collections.List[TaskEO]
+	// RemoveTasks 删除任务记录
+	RemoveTasks(taskIds []int64)
+	// ImportTasks 导入任务记录（已存在时覆盖）
+	ImportTasks(lstTask collections.List[TaskEO])
 	// Sync 同步任务组数据
 	Sync()
+	// AddVersion 保存任务组的历史定义
+	AddVersion(version VersionEO)
+	// ToVersionList 任务组的所有历史定义
+	ToVersionList(name string) collections.List[VersionEO]
+	// GetVersion 获取任务组指定版本的定义
+	GetVersion(name string, ver int) VersionEO
 }
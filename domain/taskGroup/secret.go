@@ -0,0 +1,111 @@
+package taskGroup
+
+import (
+	"FSchedule/domain/secret"
+	"errors"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/flog"
+)
+
+// ProtectSecret 加密任务组、当前任务中的Secret参数
+func (receiver *DomainObject) ProtectSecret() {
+	receiver.Data = receiver.ProtectData(receiver.Data)
+	receiver.Task.Data = receiver.ProtectData(receiver.Task.Data)
+}
+
+// ProtectData 加密参数中的Secret参数（使用轮换前的密钥加密的，重新加密），返回新的参数
+func (receiver *DomainObject) ProtectData(data collections.Dictionary[string, string]) collections.Dictionary[string, string] {
+	names := receiver.DataSchema.SecretNames()
+	cipher := secret.GetCipher()
+	if len(names) == 0 || data.IsNil() || cipher == nil || !cipher.IsEnable() {
+		return data
+	}
+
+	values := make(map[string]string)
+	for key, value := range data.ToMap() {
+		values[key] = value
+	}
+	isChange := false
+	for _, name := range names {
+		value := values[name]
+		if value == "" || (secret.IsEncrypted(value) && !cipher.NeedRotate(value)) {
+			continue
+		}
+		if secret.IsEncrypted(value) {
+			plain, err := cipher.Decrypt(value)
+			if err != nil {
+				flog.Warningf("任务组：%s 参数：%s 轮换密钥失败：%s", receiver.Name, name, err.Error())
+				continue
+			}
+			value = plain
+		}
+		encrypted, err := cipher.Encrypt(value)
+		if err != nil {
+			flog.Warningf("任务组：%s 参数：%s 加密失败：%s", receiver.Name, name, err.Error())
+			continue
+		}
+		values[name] = encrypted
+		isChange = true
+	}
+	if !isChange {
+		return data
+	}
+	return collections.NewDictionaryFromMap(values)
+}
+
+// 管理端提交的脱敏参数（未修改），使用当前的值
+func (receiver *DomainObject) restoreMasked(data collections.Dictionary[string, string]) collections.Dictionary[string, string] {
+	if data.IsNil() {
+		return data
+	}
+	values := make(map[string]string)
+	for key, value := range data.ToMap() {
+		if value == secret.Mask && !receiver.Data.IsNil() {
+			value = receiver.Data.GetValue(key)
+		}
+		values[key] = value
+	}
+	return collections.NewDictionaryFromMap(values)
+}
+
+// Masked 脱敏后的任务组，用于管理接口
+func (receiver DomainObject) Masked() DomainObject {
+	receiver.Data = secret.MaskData(receiver.Data, receiver.DataSchema.SecretNames()...)
+	receiver.Task = receiver.Task.Masked(receiver.DataSchema.SecretNames()...)
+	return receiver
+}
+
+// Masked 脱敏后的任务（names：Secret参数的名称）
+func (receiver TaskEO) Masked(names ...string) TaskEO {
+	receiver.Data = secret.MaskData(receiver.Data, names...)
+	return receiver
+}
+
+// Masked 脱敏后的历史定义
+func (receiver VersionEO) Masked() VersionEO {
+	receiver.Data = secret.MaskData(receiver.Data, receiver.DataSchema.SecretNames()...)
+	return receiver
+}
+
+// RevealData 解密后的参数，只在下发给客户端时使用
+func (receiver *TaskEO) RevealData() (collections.Dictionary[string, string], error) {
+	if receiver.Data.IsNil() {
+		return receiver.Data, nil
+	}
+	values := make(map[string]string)
+	cipher := secret.GetCipher()
+	for key, value := range receiver.Data.ToMap() {
+		if secret.IsEncrypted(value) {
+			if cipher == nil {
+				return receiver.Data, errors.New("未配置密钥")
+			}
+			plain, err := cipher.Decrypt(value)
+			if err != nil {
+				return receiver.Data, err
+			}
+			value = plain
+		}
+		values[key] = value
+	}
+	return collections.NewDictionaryFromMap(values), nil
+}
@@ -2,7 +2,11 @@ package taskGroup
 
 import (
 	"FSchedule/domain/enum"
+	"FSchedule/domain/taskEvent"
+	"fmt"
 	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/flog"
+	"strconv"
 	"time"
 )
 
@@ -21,17 +25,103 @@ type TaskEO struct {
 	SchedulerAt time.Time                              // 调度时间
 	Data        collections.Dictionary[string, string] // 本次执行任务时的Data数据
 	CreateAt    time.Time                              // 任务创建时间
+	LeaseAt     time.Time                              // 租约到期时间（客户端拉取模式）
+	QueueAt     time.Time                              // 进入排队的时间（未排队时为零值）
+	WaitTime    int64                                  // 排队等待调度的耗时（毫秒）
+	TraceParent string                                 // 调度链路（W3C traceparent）
+	Attempt     int                                    // 第几次下发给客户端（每次下发时递增，用于拒绝已被取代的上报）
+	Checkpoint  string                                 // 断点（客户端保存的续跑令牌，故障转移后交给下一个客户端）
+	Message     string                                 // 最新的进度消息
+	IsOverride  bool                                   // 手动执行时覆盖了参数（完成后不同步到任务组）
+	events      []taskEvent.DomainObject               // 未发布的状态变更事件（保存成功后由应用层发布）
 }
 
 func NewTaskDO() *TaskEO {
 	return &TaskEO{}
 }
 
-// SetClient 调度时设置客户端
-func (receiver *TaskEO) SetClient(client ClientVO) {
-	receiver.Status = enum.Working
+// SetClient 调度时设置客户端（不允许的状态变更返回false）
+func (receiver *TaskEO) SetClient(client ClientVO) bool {
+	if !receiver.Transit(enum.Working, fmt.Sprintf("下发给客户端（%d）", client.Id)) {
+		return false
+	}
 	receiver.SchedulerAt = time.Now()
 	receiver.Client = client
+	receiver.Attempt++
+	return true
+}
+
+// Restore 已下发过的任务（重试、故障转移），恢复为执行中，不再重复下发（不允许的状态变更返回false）
+func (receiver *TaskEO) Restore(client ClientVO, attempt int) bool {
+	if !receiver.Transit(enum.Working, fmt.Sprintf("已下发给客户端（%d），不再重复下发", client.Id)) {
+		return false
+	}
+	receiver.Client = client
+	receiver.Attempt = attempt
+	return true
+}
+
+// SkipAttempt 下发次数已被占用（上一次下发中断、下发给的客户端已不存在），下一次下发从其之后开始
+func (receiver *TaskEO) SkipAttempt(attempt int) {
+	if attempt > receiver.Attempt {
+		receiver.Attempt = attempt
+	}
+}
+
+// IdempotencyKey 幂等键：任务组+计划执行时间，重试、故障转移时保持不变
+func (receiver *TaskEO) IdempotencyKey() string {
+	return receiver.Name + ":" + strconv.FormatInt(receiver.StartAt.UnixMilli(), 10)
+}
+
+// IsSuperseded 客户端上报的是否为已被取代的下发（attempt为0时不校验）
+func (receiver *TaskEO) IsSuperseded(attempt int) bool {
+	return attempt > 0 && attempt < receiver.Attempt
+}
+
+// Lease 客户端拉取任务，获得租约（不允许的状态变更返回false）
+func (receiver *TaskEO) Lease(client ClientVO, leaseTime time.Duration) bool {
+	if !receiver.SetClient(client) {
+		return false
+	}
+	receiver.RunAt = time.Now()
+	receiver.LeaseAt = time.Now().Add(leaseTime)
+	return true
+}
+
+// RenewLease 客户端上报进度时，续约
+func (receiver *TaskEO) RenewLease(leaseTime time.Duration) {
+	if receiver.IsLeased() && receiver.IsWorking() {
+		receiver.LeaseAt = time.Now().Add(leaseTime)
+	}
+}
+
+// SetCheckpoint 客户端执行中保存断点、进度
+func (receiver *TaskEO) SetCheckpoint(checkpoint string, progress int, message string) {
+	receiver.Checkpoint = checkpoint
+	receiver.Message = message
+	if progress > 0 {
+		receiver.Progress = progress
+	}
+}
+
+// IsLeased 是否由客户端拉取（租约模式）
+func (receiver *TaskEO) IsLeased() bool {
+	return !receiver.LeaseAt.IsZero()
+}
+
+// IsLeaseExpired 租约是否已过期
+func (receiver *TaskEO) IsLeaseExpired() bool {
+	return receiver.IsLeased() && time.Now().After(receiver.LeaseAt)
+}
+
+// ReclaimLease 租约过期，回收任务重新调度（不允许的状态变更返回false）
+func (receiver *TaskEO) ReclaimLease() bool {
+	if !receiver.Transit(enum.ScheduleFail, "租约过期，回收任务") {
+		return false
+	}
+	receiver.Client = ClientVO{}
+	receiver.LeaseAt = time.Time{}
+	return true
 }
 
 // SetJobName 更新了JobName，则要立即更新Task的JobName
@@ -39,19 +129,39 @@ func (receiver *TaskEO) SetJobName(name string) {
 	receiver.Name = name
 }
 
-// SetFail 设备为失败
-func (receiver *TaskEO) SetFail() {
-	receiver.Status = enum.Fail
+// SetFail 设备为失败（不允许的状态变更返回false）
+func (receiver *TaskEO) SetFail(reason string) bool {
+	return receiver.Transit(enum.Fail, reason)
 }
 
 // Scheduling 调度
 func (receiver *TaskEO) Scheduling() {
-	receiver.Status = enum.Scheduling
+	receiver.Transit(enum.Scheduling, "到达执行时间")
+}
+
+// Queue 进入排队，等待调度
+func (receiver *TaskEO) Queue() {
+	if !receiver.IsQueued() {
+		receiver.QueueAt = time.Now()
+	}
+}
+
+// Dequeue 结束排队，累计等待耗时
+func (receiver *TaskEO) Dequeue() {
+	if receiver.IsQueued() {
+		receiver.WaitTime += time.Since(receiver.QueueAt).Milliseconds()
+		receiver.QueueAt = time.Time{}
+	}
 }
 
-// ScheduleFail 调度失败
-func (receiver *TaskEO) ScheduleFail() {
-	receiver.Status = enum.ScheduleFail
+// IsQueued 是否在排队中
+func (receiver *TaskEO) IsQueued() bool {
+	return receiver.Status == enum.Scheduling && !receiver.QueueAt.IsZero()
+}
+
+// ScheduleFail 调度失败（不允许的状态变更返回false）
+func (receiver *TaskEO) ScheduleFail(reason string) bool {
+	return receiver.Transit(enum.ScheduleFail, reason)
 }
 
 // IsNull 未分配
@@ -59,9 +169,9 @@ func (receiver *TaskEO) IsNull() bool {
 	return receiver.Id == 0 && receiver.Caption == "" && receiver.Name == ""
 }
 
-// IsFinish 是否完成
+// IsFinish 是否完成（跳过的任务不会再执行，也视为完成）
 func (receiver *TaskEO) IsFinish() bool {
-	return receiver.Status == enum.Success || receiver.Status == enum.Fail
+	return receiver.Status == enum.Success || receiver.Status == enum.Fail || receiver.Status == enum.Skip
 }
 
 // IsWorking 是否为执行中
@@ -69,11 +179,35 @@ func (receiver *TaskEO) IsWorking() bool {
 	return receiver.Status == enum.Working
 }
 
-// UpdateTask 更新任务
-func (receiver *TaskEO) UpdateTask(status enum.TaskStatus, data collections.Dictionary[string, string], progress int, speed int64) {
-	receiver.Status = status
+// UpdateTask 客户端上报，更新任务（不允许的状态变更返回false，如已完成的任务收到延迟的执行中上报）
+func (receiver *TaskEO) UpdateTask(status enum.TaskStatus, data collections.Dictionary[string, string], progress int, speed int64) bool {
+	if !receiver.Transit(status, "客户端上报") {
+		return false
+	}
 	receiver.Data = data
 	receiver.Progress = progress
 	receiver.RunSpeed = speed
 	receiver.RunAt = time.Now()
+	return true
+}
+
+// Transit 按状态机变更状态，并记录状态变更，不允许的变更返回false
+func (receiver *TaskEO) Transit(status enum.TaskStatus, reason string) bool {
+	if receiver.Status == status {
+		return true
+	}
+	if !receiver.Status.CanTransit(status) {
+		flog.Warningf("任务组：%s %d 不允许的状态变更：%s → %s（%s）", receiver.Name, receiver.Id, receiver.Status.String(), status.String(), reason)
+		return false
+	}
+	receiver.events = append(receiver.events, taskEvent.New(receiver.Name, receiver.Id, receiver.Status, status, reason))
+	receiver.Status = status
+	return true
+}
+
+// PopEvents 取出未发布的状态变更事件
+func (receiver *TaskEO) PopEvents() []taskEvent.DomainObject {
+	events := receiver.events
+	receiver.events = nil
+	return events
 }
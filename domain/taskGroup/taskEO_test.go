@@ -0,0 +1,112 @@
+package taskGroup
+
+import (
+	"FSchedule/domain/enum"
+	"testing"
+	"time"
+)
+
+func TestTaskStatusCanTransit(t *testing.T) {
+	cases := []struct {
+		from, to enum.TaskStatus
+		allow    bool
+	}{
+		{enum.None, enum.Scheduling, true},
+		{enum.None, enum.Working, false},
+		{enum.Scheduling, enum.Working, true},
+		{enum.ScheduleFail, enum.Scheduling, true},
+		{enum.Working, enum.ScheduleFail, true},
+		{enum.Working, enum.Scheduling, false},
+		{enum.Working, enum.Working, true},
+		{enum.Success, enum.Working, false},
+		{enum.Fail, enum.Success, false},
+		{enum.Skip, enum.Fail, false},
+	}
+	for _, c := range cases {
+		if c.from.CanTransit(c.to) != c.allow {
+			t.Errorf("%s → %s 期望：%v", c.from.String(), c.to.String(), c.allow)
+		}
+	}
+}
+
+func TestTaskEOTransitRecordsEvents(t *testing.T) {
+	task := TaskEO{Id: 1, Name: "job"}
+	task.Scheduling()
+	if !task.SetClient(ClientVO{Id: 10}) {
+		t.Fatal("Scheduling → Working 应允许")
+	}
+	if task.Attempt != 1 || task.Client.Id != 10 {
+		t.Fatalf("下发后 Attempt=%d ClientId=%d", task.Attempt, task.Client.Id)
+	}
+
+	events := task.PopEvents()
+	if len(events) != 2 || events[0].ToStatus != enum.Scheduling || events[1].FromStatus != enum.Scheduling || events[1].ToStatus != enum.Working {
+		t.Fatalf("状态变更事件不正确：%+v", events)
+	}
+	if len(task.PopEvents()) != 0 {
+		t.Fatal("取出后应清空事件")
+	}
+}
+
+func TestTaskEORejectedTransitKeepsState(t *testing.T) {
+	task := TaskEO{Id: 1, Name: "job", Status: enum.Success, Client: ClientVO{Id: 10}, Attempt: 2, LeaseAt: time.Now()}
+
+	if task.SetClient(ClientVO{Id: 20}) {
+		t.Fatal("Success → Working 应拒绝")
+	}
+	if task.Restore(ClientVO{Id: 20}, 5) {
+		t.Fatal("Success → Working 应拒绝")
+	}
+	if task.ReclaimLease() {
+		t.Fatal("Success → ScheduleFail 应拒绝")
+	}
+	if task.SetFail("客户端下线") || task.ScheduleFail("调度失败") {
+		t.Fatal("终态不能再变更")
+	}
+	if task.Status != enum.Success || task.Client.Id != 10 || task.Attempt != 2 || task.LeaseAt.IsZero() {
+		t.Fatalf("拒绝的变更不应修改任务：%+v", task)
+	}
+	if len(task.PopEvents()) != 0 {
+		t.Fatal("拒绝的变更不应记录事件")
+	}
+}
+
+func TestDomainObjectKeepsEventsOfReplacedTask(t *testing.T) {
+	do := DomainObject{Name: "job", Task: TaskEO{Id: 1, Name: "job", Status: enum.Working}, NextAt: time.Now()}
+	if !do.ClientOffline() {
+		t.Fatal("Working → Fail 应允许")
+	}
+	do.CreateTask()
+	events := do.PopEvents()
+	if len(events) != 1 || events[0].TaskId != 1 || events[0].ToStatus != enum.Fail {
+		t.Fatalf("替换任务后应保留原任务的事件：%+v", events)
+	}
+	if len(do.PopEvents()) != 0 {
+		t.Fatal("取出后应清空事件")
+	}
+}
+
+func TestDomainObjectSetClientRejected(t *testing.T) {
+	do := DomainObject{Name: "job", Task: TaskEO{Id: 1, Name: "job", Status: enum.Fail}}
+	if do.SetClient(ClientVO{Id: 10}) {
+		t.Fatal("Fail → Working 应拒绝")
+	}
+	if do.Task.Client.Id != 0 || do.Task.Attempt != 0 || !do.Task.RunAt.IsZero() {
+		t.Fatalf("拒绝的变更不应修改任务：%+v", do.Task)
+	}
+}
+
+func TestTaskEOSkipAttempt(t *testing.T) {
+	task := TaskEO{Id: 1, Name: "job", Attempt: 2}
+	task.Scheduling()
+	// 第3次下发已中断，下一次下发为第4次，第3次的上报被拒绝
+	task.SkipAttempt(3)
+	if !task.SetClient(ClientVO{Id: 10}) || task.Attempt != 4 || !task.IsSuperseded(3) {
+		t.Fatalf("跳过中断的下发次数后 Attempt=%d", task.Attempt)
+	}
+	// 已使用的下发次数不回退
+	task.SkipAttempt(1)
+	if task.Attempt != 4 {
+		t.Fatalf("下发次数不应回退：%d", task.Attempt)
+	}
+}
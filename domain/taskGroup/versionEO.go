@@ -0,0 +1,29 @@
+package taskGroup
+
+import (
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/collections"
+	"time"
+)
+
+// VersionEO 任务组的历史定义
+type VersionEO struct {
+	Name              string                                 // 实现Job的特性名称（客户端识别哪个实现类）
+	Ver               int                                    // 版本
+	Caption           string                                 // 任务组标题
+	Cron              string                                 // 时间定时器表达式
+	StartAt           time.Time                              // 开始时间
+	IsEnable          bool                                   // 是否开启
+	Data              collections.Dictionary[string, string] // 传给客户端的参数
+	DataSchema        DataSchemaVO                           // 参数的定义
+	ConcurrencyPolicy enum.ConcurrencyPolicy                 // 任务执行中到达下一个周期时的处理策略
+	MaxParallel       int                                    // 最多同时执行的任务数量（Allow策略，0不限制）
+	Priority          int                                    // 优先级（排队时，数值越大越先调度）
+	Namespace         string                                 // 命名空间
+	CreateAt          time.Time                              // 版本创建时间
+}
+
+// IsNil 版本不存在
+func (receiver *VersionEO) IsNil() bool {
+	return receiver.Name == ""
+}
@@ -0,0 +1,9 @@
+package taskLive
+
+// IHub 当前节点的实时订阅者
+type IHub interface {
+	// Push 推送给当前节点符合条件的订阅者（不阻塞，订阅者来不及接收时丢弃）
+	Push(events ...EventVO)
+	// Names 当前节点的订阅者订阅的任务组（name为空时订阅所有任务组）
+	Names() []string
+}
@@ -0,0 +1,66 @@
+package taskLive
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
+// EventVO 实时推送给订阅者的事件（任务日志、任务状态变更）
+type EventVO struct {
+	Type       enum.LiveEventType // 事件类型
+	Name       string             // 任务组名称
+	TaskId     int64              // 任务ID
+	Ver        int                // 版本
+	Status     enum.TaskStatus    // 任务状态
+	Progress   int                // 进度0-100
+	ClientId   int64              // 执行任务的客户端
+	ClientName string             // 客户端名称
+	LogLevel   eumLogLevel.Enum   // 日志级别
+	Content    string             // 日志内容
+	At         time.Time          // 发生时间
+}
+
+// NewLog 任务日志
+func NewLog(do taskLog.DomainObject) EventVO {
+	return EventVO{
+		Type:     enum.LiveLog,
+		Name:     do.Name,
+		TaskId:   do.TaskId,
+		Ver:      do.Ver,
+		LogLevel: do.LogLevel,
+		Content:  do.Content,
+		At:       do.CreateAt,
+	}
+}
+
+// NewStatus 任务组当前任务的状态
+func NewStatus(do taskGroup.DomainObject) EventVO {
+	return EventVO{
+		Type:       enum.LiveStatus,
+		Name:       do.Name,
+		TaskId:     do.Task.Id,
+		Ver:        do.Task.Ver,
+		Status:     do.Task.Status,
+		Progress:   do.Task.Progress,
+		ClientId:   do.Task.Client.Id,
+		ClientName: do.Task.Client.Name,
+		Content:    do.Task.Message,
+		At:         time.Now(),
+	}
+}
+
+// IsMatch 是否符合订阅条件（name为空时订阅所有任务组，taskId为0时订阅所有任务）
+func (receiver *EventVO) IsMatch(name string, taskId int64) bool {
+	if name != "" && receiver.Name != name {
+		return false
+	}
+	return taskId == 0 || receiver.TaskId == taskId
+}
+
+// IsSameStatus 与上一次推送的状态相同（任务组保存时，大部分情况状态不会变化）
+func (receiver *EventVO) IsSameStatus(last EventVO) bool {
+	return receiver.TaskId == last.TaskId && receiver.Status == last.Status && receiver.Progress == last.Progress && receiver.ClientId == last.ClientId && receiver.Content == last.Content
+}
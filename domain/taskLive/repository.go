@@ -0,0 +1,8 @@
+package taskLive
+
+type Repository interface {
+	// Subscribe 记录当前节点订阅的任务组（name为空时订阅所有任务组），超过有效期未刷新时失效
+	Subscribe(names []string)
+	// HasSubscriber 集群中是否有节点订阅了任务组
+	HasSubscriber(name string) bool
+}
@@ -1,6 +1,38 @@
 package taskLog
 
+import (
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
 type Repository interface {
 	// Add 添加日志
 	Add(taskLogDO DomainObject)
+	// Flush 等待队列中的日志写入完成（超时返回false）
+	Flush(timeout time.Duration) bool
+	// ToList 获取任务组的日志（afterId：只返回该ID之后的日志）
+	ToList(name string, taskId int64, afterId int64, top int) collections.List[DomainObject]
+	// Search 搜索日志（Keyword使用数据库的全文索引）
+	Search(filter FilterVO, pageSize int, pageIndex int) collections.PageList[DomainObject]
+	// ToListByTaskIds 获取任务的所有日志
+	ToListByTaskIds(taskIds []int64) collections.List[DomainObject]
+	// RemoveByTaskIds 删除任务的所有日志
+	RemoveByTaskIds(taskIds []int64)
+	// Import 导入日志（已存在时覆盖）
+	Import(lstLog collections.List[DomainObject])
+	// Clear 清除任务组指定级别、早于before的日志，每次最多清除top条，返回清除的数量
+	Clear(name string, logLevel eumLogLevel.Enum, before time.Time, top int) int64
+	// ClearExcept 清除不在names中的任务组（包括已删除的任务组）指定级别、早于before的日志，每次最多清除top条，返回清除的数量
+	ClearExcept(names []string, logLevel eumLogLevel.Enum, before time.Time, top int) int64
+}
+
+// FilterVO 日志的查询条件（零值表示不过滤）
+type FilterVO struct {
+	Name     string            // 任务组名称
+	TaskId   int64             // 任务ID
+	LogLevel *eumLogLevel.Enum // 日志级别（大于等于）
+	Keyword  string            // 日志内容的关键字
+	StartAt  time.Time         // 开始时间
+	EndAt    time.Time         // 结束时间
 }
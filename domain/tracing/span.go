@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"github.com/farseer-go/fs/container"
+)
+
+// HeaderName W3C Trace Context的请求头
+const HeaderName = "traceparent"
+
+// Attribute Span的属性
+type Attribute struct {
+	Key   string
+	Value any // string、int、int64
+}
+
+// Span 链路中的一次操作
+type Span interface {
+	// TraceParent 当前Span的W3C traceparent，用于传递给下游
+	TraceParent() string
+	// SetAttributes 设置属性
+	SetAttributes(attrs ...Attribute)
+	// AddEvent 记录事件
+	AddEvent(name string, attrs ...Attribute)
+	// Error 记录错误（err为nil时忽略）
+	Error(err error)
+	// End 结束Span
+	End()
+}
+
+// Tracer 链路追踪（由基础设施层根据配置实现）
+type Tracer interface {
+	// Start 开始一个Span，traceParent为上游的W3C traceparent（为空时开始新的链路）
+	Start(traceParent string, name string, attrs ...Attribute) Span
+}
+
+// Start 开始一个Span，traceParent为上游的W3C traceparent（为空时开始新的链路）
+func Start(traceParent string, name string, attrs ...Attribute) Span {
+	if !container.IsRegister[Tracer]() {
+		return emptySpan{traceParent: traceParent}
+	}
+	return container.Resolve[Tracer]().Start(traceParent, name, attrs...)
+}
+
+// String 字符串属性
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int 整数属性
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 整数属性
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Task 任务的通用属性
+func Task(name string, taskId int64) []Attribute {
+	return []Attribute{String("fschedule.task_group", name), Int64("fschedule.task_id", taskId)}
+}
+
+// emptySpan 未注册Tracer时使用，不记录，原样传递上游的链路
+type emptySpan struct {
+	traceParent string
+}
+
+func (receiver emptySpan) TraceParent() string           { return receiver.traceParent }
+func (receiver emptySpan) SetAttributes(...Attribute)    {}
+func (receiver emptySpan) AddEvent(string, ...Attribute) {}
+func (receiver emptySpan) Error(error)                   {}
+func (receiver emptySpan) End()                          {}
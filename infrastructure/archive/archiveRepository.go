@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"FSchedule/domain/archive"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"fmt"
+	"github.com/farseer-go/collections"
+	"sort"
+	"strings"
+	"time"
+)
+
+type archiveRepository struct {
+	config  archiveConfig
+	storage storage
+	prefix  string // 存储中的Key前缀
+}
+
+func (receiver *archiveRepository) IsEnable() bool {
+	return receiver.storage != nil
+}
+
+func (receiver *archiveRepository) Save(name string, lstTask collections.List[taskGroup.TaskEO], lstLog collections.List[taskLog.DomainObject]) (string, error) {
+	if lstTask.Count() == 0 {
+		return "", nil
+	}
+	taskRows := make([]taskRow, 0, lstTask.Count())
+	for _, task := range lstTask.ToArray() {
+		taskRows = append(taskRows, toTaskRow(task))
+	}
+	logRows := make([]logRow, 0, lstLog.Count())
+	for _, log := range lstLog.ToArray() {
+		logRows = append(logRows, toLogRow(log))
+	}
+
+	// 任务组名称/归档日期/首个任务ID-最后任务ID
+	key := fmt.Sprintf("%s/%s/%d-%d", safeName(name), time.Now().Format("20060102"), taskRows[0].Id, taskRows[len(taskRows)-1].Id)
+	ext := fileExt[receiver.config.Format]
+	logContent, err := encode(receiver.config.Format, logRows)
+	if err != nil {
+		return "", err
+	}
+	taskContent, err := encode(receiver.config.Format, taskRows)
+	if err != nil {
+		return "", err
+	}
+	// 任务文件最后写入，存在任务文件即表示归档完整
+	if err = receiver.storage.put(receiver.prefix+key+ext.log, logContent); err != nil {
+		return "", err
+	}
+	if err = receiver.storage.put(receiver.prefix+key+ext.task, taskContent); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (receiver *archiveRepository) ToList(name string) (collections.List[archive.FileVO], error) {
+	lst := collections.NewList[archive.FileVO]()
+	if !receiver.IsEnable() {
+		return lst, nil
+	}
+	lstObject, err := receiver.storage.list(receiver.prefix + safeName(name) + "/")
+	if err != nil {
+		return lst, err
+	}
+
+	files := make(map[string]*archive.FileVO)
+	for _, object := range lstObject {
+		key, format, isTask := parseKey(strings.TrimPrefix(object.Key, receiver.prefix))
+		if key == "" {
+			continue
+		}
+		file, exists := files[key]
+		if !exists {
+			file = &archive.FileVO{Key: key, Name: name, Format: format}
+			files[key] = file
+		}
+		file.Size += object.Size
+		if isTask {
+			file.CreateAt = object.LastModified
+		}
+	}
+
+	// 只返回完整的归档（存在任务文件）
+	var arr []archive.FileVO
+	for _, file := range files {
+		if !file.CreateAt.IsZero() {
+			arr = append(arr, *file)
+		}
+	}
+	sort.Slice(arr, func(i, j int) bool { return arr[i].CreateAt.After(arr[j].CreateAt) })
+	lst.Add(arr...)
+	return lst, nil
+}
+
+func (receiver *archiveRepository) Load(key string) (collections.List[taskGroup.TaskEO], collections.List[taskLog.DomainObject], error) {
+	lstTask := collections.NewList[taskGroup.TaskEO]()
+	lstLog := collections.NewList[taskLog.DomainObject]()
+	if !receiver.IsEnable() {
+		return lstTask, lstLog, fmt.Errorf("未开启归档")
+	}
+	key = strings.Trim(key, "/")
+	if key == "" || strings.Contains(key, "..") {
+		return lstTask, lstLog, fmt.Errorf("归档Key不正确：%s", key)
+	}
+
+	// 归档时的格式可能与当前配置不同，按文件后缀确定
+	for _, format := range []string{receiver.config.Format, formatJsonl, formatParquet} {
+		ext := fileExt[format]
+		taskContent, err := receiver.storage.get(receiver.prefix + key + ext.task)
+		if err != nil {
+			continue
+		}
+		taskRows, err := decode[taskRow](format, taskContent)
+		if err != nil {
+			return lstTask, lstLog, err
+		}
+		logContent, err := receiver.storage.get(receiver.prefix + key + ext.log)
+		if err != nil {
+			return lstTask, lstLog, err
+		}
+		logRows, err := decode[logRow](format, logContent)
+		if err != nil {
+			return lstTask, lstLog, err
+		}
+		for _, row := range taskRows {
+			lstTask.Add(row.toEO())
+		}
+		for _, row := range logRows {
+			lstLog.Add(row.toDO())
+		}
+		return lstTask, lstLog, nil
+	}
+	return lstTask, lstLog, fmt.Errorf("归档不存在：%s", key)
+}
+
+// parseKey 根据文件后缀，得到归档Key、格式、是否为任务文件
+func parseKey(objectKey string) (key string, format string, isTask bool) {
+	for f, ext := range fileExt {
+		if strings.HasSuffix(objectKey, ext.task) {
+			return strings.TrimSuffix(objectKey, ext.task), f, true
+		}
+		if strings.HasSuffix(objectKey, ext.log) {
+			return strings.TrimSuffix(objectKey, ext.log), f, false
+		}
+	}
+	return "", "", false
+}
+
+// safeName 任务组名称作为路径时，去掉路径分隔符
+func safeName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name)
+}
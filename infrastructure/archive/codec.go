@@ -0,0 +1,248 @@
+package archive
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/writer"
+	"time"
+)
+
+const (
+	formatJsonl   = "jsonl"   // 每行一个json，gzip压缩
+	formatParquet = "parquet" // 列式存储，snappy压缩
+)
+
+// 归档文件的后缀
+var fileExt = map[string]struct{ task, log string }{
+	formatJsonl:   {task: ".tasks.jsonl.gz", log: ".logs.jsonl.gz"},
+	formatParquet: {task: ".tasks.parquet", log: ".logs.parquet"},
+}
+
+// taskRow 归档文件中的任务（时间为Unix毫秒，字段与任务表一一对应）
+type taskRow struct {
+	Id          int64  `json:"id" parquet:"name=id, type=INT64"`
+	Name        string `json:"name" parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Ver         int32  `json:"ver" parquet:"name=ver, type=INT32"`
+	Caption     string `json:"caption" parquet:"name=caption, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	StartAt     int64  `json:"startAt" parquet:"name=start_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	RunAt       int64  `json:"runAt" parquet:"name=run_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	RunSpeed    int64  `json:"runSpeed" parquet:"name=run_speed, type=INT64"`
+	ClientId    int64  `json:"clientId" parquet:"name=client_id, type=INT64"`
+	ClientIp    string `json:"clientIp" parquet:"name=client_ip, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ClientName  string `json:"clientName" parquet:"name=client_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Progress    int32  `json:"progress" parquet:"name=progress, type=INT32"`
+	Status      int32  `json:"status" parquet:"name=status, type=INT32"`
+	SchedulerAt int64  `json:"schedulerAt" parquet:"name=scheduler_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Data        string `json:"data" parquet:"name=data, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreateAt    int64  `json:"createAt" parquet:"name=create_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	WaitTime    int64  `json:"waitTime" parquet:"name=wait_time, type=INT64"`
+	QueueAt     int64  `json:"queueAt" parquet:"name=queue_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Attempt     int32  `json:"attempt" parquet:"name=attempt, type=INT32"`
+	Checkpoint  string `json:"checkpoint" parquet:"name=checkpoint, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Message     string `json:"message" parquet:"name=message, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IsOverride  bool   `json:"isOverride" parquet:"name=is_override, type=BOOLEAN"`
+}
+
+// logRow 归档文件中的日志（时间为Unix毫秒）
+type logRow struct {
+	Id       int64  `json:"id" parquet:"name=id, type=INT64"`
+	Name     string `json:"name" parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Ver      int32  `json:"ver" parquet:"name=ver, type=INT32"`
+	Caption  string `json:"caption" parquet:"name=caption, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TaskId   int64  `json:"taskId" parquet:"name=task_id, type=INT64"`
+	Data     string `json:"data" parquet:"name=data, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LogLevel int32  `json:"logLevel" parquet:"name=log_level, type=INT32"`
+	Content  string `json:"content" parquet:"name=content, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreateAt int64  `json:"createAt" parquet:"name=create_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+func toTaskRow(task taskGroup.TaskEO) taskRow {
+	return taskRow{
+		Id:          task.Id,
+		Name:        task.Name,
+		Ver:         int32(task.Ver),
+		Caption:     task.Caption,
+		StartAt:     toMilli(task.StartAt),
+		RunAt:       toMilli(task.RunAt),
+		RunSpeed:    task.RunSpeed,
+		ClientId:    task.Client.Id,
+		ClientIp:    task.Client.Ip,
+		ClientName:  task.Client.Name,
+		Progress:    int32(task.Progress),
+		Status:      int32(task.Status),
+		SchedulerAt: toMilli(task.SchedulerAt),
+		Data:        marshalData(task.Data),
+		CreateAt:    toMilli(task.CreateAt),
+		WaitTime:    task.WaitTime,
+		QueueAt:     toMilli(task.QueueAt),
+		Attempt:     int32(task.Attempt),
+		Checkpoint:  task.Checkpoint,
+		Message:     task.Message,
+		IsOverride:  task.IsOverride,
+	}
+}
+
+func (receiver taskRow) toEO() taskGroup.TaskEO {
+	return taskGroup.TaskEO{
+		Id:          receiver.Id,
+		Name:        receiver.Name,
+		Ver:         int(receiver.Ver),
+		Caption:     receiver.Caption,
+		StartAt:     fromMilli(receiver.StartAt),
+		RunAt:       fromMilli(receiver.RunAt),
+		RunSpeed:    receiver.RunSpeed,
+		Client:      taskGroup.ClientVO{Id: receiver.ClientId, Name: receiver.ClientName, Ip: receiver.ClientIp},
+		Progress:    int(receiver.Progress),
+		Status:      enum.TaskStatus(receiver.Status),
+		SchedulerAt: fromMilli(receiver.SchedulerAt),
+		Data:        unmarshalData(receiver.Data),
+		CreateAt:    fromMilli(receiver.CreateAt),
+		WaitTime:    receiver.WaitTime,
+		QueueAt:     fromMilli(receiver.QueueAt),
+		Attempt:     int(receiver.Attempt),
+		Checkpoint:  receiver.Checkpoint,
+		Message:     receiver.Message,
+		IsOverride:  receiver.IsOverride,
+	}
+}
+
+func toLogRow(log taskLog.DomainObject) logRow {
+	return logRow{
+		Id:       log.Id,
+		Name:     log.Name,
+		Ver:      int32(log.Ver),
+		Caption:  log.Caption,
+		TaskId:   log.TaskId,
+		Data:     marshalData(log.Data),
+		LogLevel: int32(log.LogLevel),
+		Content:  log.Content,
+		CreateAt: toMilli(log.CreateAt),
+	}
+}
+
+func (receiver logRow) toDO() taskLog.DomainObject {
+	return taskLog.DomainObject{
+		Id:       receiver.Id,
+		Name:     receiver.Name,
+		Ver:      int(receiver.Ver),
+		Caption:  receiver.Caption,
+		TaskId:   receiver.TaskId,
+		Data:     unmarshalData(receiver.Data),
+		LogLevel: eumLogLevel.Enum(receiver.LogLevel),
+		Content:  receiver.Content,
+		CreateAt: fromMilli(receiver.CreateAt),
+	}
+}
+
+// encode 将记录编码为指定格式的文件内容
+func encode[T any](format string, rows []T) ([]byte, error) {
+	var buf bytes.Buffer
+	if format == formatParquet {
+		pw, err := writer.NewParquetWriterFromWriter(&buf, new(T), 1)
+		if err != nil {
+			return nil, err
+		}
+		pw.CompressionType = parquet.CompressionCodec_SNAPPY
+		for _, row := range rows {
+			if err = pw.Write(row); err != nil {
+				return nil, err
+			}
+		}
+		if err = pw.WriteStop(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	encoder.SetEscapeHTML(false)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode 读取文件内容中的记录
+func decode[T any](format string, content []byte) ([]T, error) {
+	if format == formatParquet {
+		file, err := buffer.NewBufferFile(content)
+		if err != nil {
+			return nil, err
+		}
+		pr, err := reader.NewParquetReader(file, new(T), 1)
+		if err != nil {
+			return nil, err
+		}
+		defer pr.ReadStop()
+		rows := make([]T, pr.GetNumRows())
+		if err = pr.Read(&rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	var rows []T
+	scanner := bufio.NewScanner(gz)
+	// 单条日志内容可能较大
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var row T
+		if err = json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+func toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+func marshalData(data collections.Dictionary[string, string]) string {
+	if data.Count() == 0 {
+		return "{}"
+	}
+	ba, _ := json.Marshal(data.ToMap())
+	return string(ba)
+}
+
+func unmarshalData(data string) collections.Dictionary[string, string] {
+	m := make(map[string]string)
+	_ = json.Unmarshal([]byte(data), &m)
+	return collections.NewDictionaryFromMap(m)
+}
@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testTask() taskGroup.TaskEO {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	return taskGroup.TaskEO{
+		Id:          1001,
+		Name:        "job",
+		Ver:         3,
+		Caption:     "测试任务",
+		StartAt:     now.Add(-time.Minute),
+		RunAt:       now,
+		RunSpeed:    120,
+		Client:      taskGroup.ClientVO{Id: 10, Name: "client", Ip: "127.0.0.1"},
+		Progress:    100,
+		Status:      enum.Success,
+		SchedulerAt: now.Add(-time.Second),
+		Data:        collections.NewDictionaryFromMap(map[string]string{"date": "2024-01-01", "token": "enc:k1:YWJj"}),
+		CreateAt:    now.Add(-2 * time.Minute),
+		WaitTime:    35,
+		QueueAt:     now.Add(-90 * time.Second),
+		Attempt:     2,
+		Checkpoint:  `{"offset":5000}`,
+		Message:     "处理到第5000条",
+		IsOverride:  true,
+	}
+}
+
+func TestTaskRowRoundTrip(t *testing.T) {
+	for _, format := range []string{formatJsonl, formatParquet} {
+		task := testTask()
+		content, err := encode(format, []taskRow{toTaskRow(task)})
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		rows, err := decode[taskRow](format, content)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%s 记录数：%d", format, len(rows))
+		}
+		assertTask(t, format, task, rows[0].toEO())
+	}
+}
+
+func TestLogRowRoundTrip(t *testing.T) {
+	log := taskLog.DomainObject{
+		Id:       2001,
+		Name:     "job",
+		Ver:      3,
+		Caption:  "测试任务",
+		TaskId:   1001,
+		Data:     collections.NewDictionaryFromMap(map[string]string{"date": "2024-01-01"}),
+		LogLevel: eumLogLevel.Warning,
+		Content:  "第一行\n第二行",
+		CreateAt: time.UnixMilli(time.Now().UnixMilli()),
+	}
+	for _, format := range []string{formatJsonl, formatParquet} {
+		content, err := encode(format, []logRow{toLogRow(log)})
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		rows, err := decode[logRow](format, content)
+		if err != nil || len(rows) != 1 {
+			t.Fatalf("%s 记录数：%d，%v", format, len(rows), err)
+		}
+		actual := rows[0].toDO()
+		if actual.Id != log.Id || actual.Name != log.Name || actual.Ver != log.Ver || actual.Caption != log.Caption || actual.TaskId != log.TaskId ||
+			actual.LogLevel != log.LogLevel || actual.Content != log.Content || !actual.CreateAt.Equal(log.CreateAt) || !reflect.DeepEqual(actual.Data.ToMap(), log.Data.ToMap()) {
+			t.Fatalf("%s 日志不一致：%+v", format, actual)
+		}
+	}
+}
+
+// 任务表新增字段时，归档文件也要带上（IsRestored由导入时设置，不归档）
+func TestTaskRowCoversTaskPO(t *testing.T) {
+	rowType := reflect.TypeOf(taskRow{})
+	poType := reflect.TypeOf(model.TaskPO{})
+	for i := 0; i < poType.NumField(); i++ {
+		name := poType.Field(i).Name
+		if name == "IsRestored" {
+			continue
+		}
+		if _, ok := rowType.FieldByName(name); !ok {
+			t.Fatalf("归档文件缺少任务字段：%s", name)
+		}
+	}
+}
+
+func TestTaskRowZeroTime(t *testing.T) {
+	task := testTask()
+	task.RunAt = time.Time{}
+	task.Data = collections.NewDictionary[string, string]()
+	row := toTaskRow(task)
+	if row.RunAt != 0 || row.Data != "{}" {
+		t.Fatalf("零值时间、空参数：%+v", row)
+	}
+	if actual := row.toEO(); !actual.RunAt.IsZero() || actual.Data.Count() != 0 {
+		t.Fatalf("零值时间、空参数还原：%+v", actual)
+	}
+}
+
+func assertTask(t *testing.T, format string, expect taskGroup.TaskEO, actual taskGroup.TaskEO) {
+	t.Helper()
+	if actual.Id != expect.Id || actual.Name != expect.Name || actual.Ver != expect.Ver || actual.Caption != expect.Caption ||
+		actual.RunSpeed != expect.RunSpeed || actual.Client != expect.Client || actual.Progress != expect.Progress || actual.Status != expect.Status ||
+		actual.WaitTime != expect.WaitTime || actual.Attempt != expect.Attempt || actual.Checkpoint != expect.Checkpoint ||
+		actual.Message != expect.Message || actual.IsOverride != expect.IsOverride {
+		t.Fatalf("%s 任务不一致：%+v", format, actual)
+	}
+	for _, pair := range [][2]time.Time{{expect.StartAt, actual.StartAt}, {expect.RunAt, actual.RunAt}, {expect.SchedulerAt, actual.SchedulerAt}, {expect.CreateAt, actual.CreateAt}, {expect.QueueAt, actual.QueueAt}} {
+		if !pair[0].Equal(pair[1]) {
+			t.Fatalf("%s 时间不一致：%s，%s", format, pair[0], pair[1])
+		}
+	}
+	if !reflect.DeepEqual(actual.Data.ToMap(), expect.Data.ToMap()) {
+		t.Fatalf("%s 参数不一致：%v", format, actual.Data.ToMap())
+	}
+}
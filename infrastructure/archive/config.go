@@ -0,0 +1,44 @@
+package archive
+
+import (
+	"github.com/farseer-go/fs/configure"
+	"strings"
+)
+
+// archiveConfig 归档配置（FSchedule.Archive）
+type archiveConfig struct {
+	Type      string // 存储类型：local、s3（空：不归档，直接删除）
+	Format    string // 文件格式：jsonl（gzip压缩）、parquet（默认jsonl）
+	Path      string // local：归档目录（默认./archive）；s3：Key的前缀
+	Endpoint  string // s3：服务地址，如https://s3.us-east-1.amazonaws.com、http://127.0.0.1:9000
+	Region    string // s3：区域（默认us-east-1）
+	Bucket    string // s3：存储桶
+	AccessKey string // s3：AccessKey
+	SecretKey string // s3：SecretKey
+}
+
+func getConfig() archiveConfig {
+	// 支持连接字符串（Type=local,Path=./archive）、yaml子节点两种配置方式
+	var config archiveConfig
+	if len(configure.GetSubNodes("FSchedule.Archive")) > 0 {
+		config = configure.ParseConfig[archiveConfig]("FSchedule.Archive")
+	} else {
+		config = configure.ParseString[archiveConfig](configure.GetString("FSchedule.Archive"))
+	}
+
+	config.Type = strings.ToLower(config.Type)
+	config.Format = strings.ToLower(config.Format)
+	if config.Format != formatParquet {
+		config.Format = formatJsonl
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Type == storageLocal && config.Path == "" {
+		config.Path = "./archive"
+	}
+	if config.Type == storageS3 {
+		config.Path = strings.Trim(config.Path, "/")
+	}
+	return config
+}
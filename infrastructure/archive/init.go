@@ -0,0 +1,36 @@
+package archive
+
+import (
+	"FSchedule/domain/archive"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+)
+
+// InitArchive 根据配置（FSchedule.Archive）注册归档仓储
+func InitArchive() {
+	config := getConfig()
+	repository := &archiveRepository{config: config}
+	switch config.Type {
+	case "":
+	case storageLocal:
+		repository.storage = &localStorage{root: config.Path}
+	case storageS3:
+		if config.Endpoint == "" || config.Bucket == "" {
+			_ = flog.Error("归档：S3需要配置Endpoint、Bucket，历史任务将直接删除")
+			break
+		}
+		repository.storage = newS3Storage(config)
+		if config.Path != "" {
+			repository.prefix = config.Path + "/"
+		}
+	default:
+		_ = flog.Errorf("归档：不支持的类型：%s，历史任务将直接删除", config.Type)
+	}
+	if repository.IsEnable() {
+		flog.Infof("归档：历史任务及日志将以%s格式归档到%s", config.Format, config.Type)
+	}
+
+	container.Register(func() archive.Repository {
+		return repository
+	})
+}
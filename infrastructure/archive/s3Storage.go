@@ -0,0 +1,138 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3Storage S3兼容的对象存储（使用path-style地址，AWS Signature V4签名）
+type s3Storage struct {
+	config archiveConfig
+	client *http.Client
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+func newS3Storage(config archiveConfig) *s3Storage {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &s3Storage{config: config, client: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+func (receiver *s3Storage) put(key string, content []byte) error {
+	_, err := receiver.do(http.MethodPut, "/"+receiver.config.Bucket+"/"+key, nil, content)
+	return err
+}
+
+func (receiver *s3Storage) get(key string) ([]byte, error) {
+	return receiver.do(http.MethodGet, "/"+receiver.config.Bucket+"/"+key, nil, nil)
+}
+
+func (receiver *s3Storage) list(prefix string) ([]objectVO, error) {
+	var lst []objectVO
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		body, err := receiver.do(http.MethodGet, "/"+receiver.config.Bucket, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, item := range result.Contents {
+			lst = append(lst, objectVO{Key: item.Key, Size: item.Size, LastModified: item.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return lst, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+func (receiver *s3Storage) do(method string, path string, query url.Values, payload []byte) ([]byte, error) {
+	endpoint, err := url.Parse(receiver.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	canonicalUri := escapePath(path)
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+	reqUrl := receiver.config.Endpoint + canonicalUri
+	if canonicalQuery != "" {
+		reqUrl += "?" + canonicalQuery
+	}
+	req, err := http.NewRequest(method, reqUrl, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	// 签名
+	canonicalHeaders := "host:" + endpoint.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n"
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{method, canonicalUri, canonicalQuery, canonicalHeaders, signedHeaders, payloadHash}, "\n")
+	scope := date + "/" + receiver.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signingKey := hmacSha256([]byte("AWS4"+receiver.config.SecretKey), date)
+	signingKey = hmacSha256(signingKey, receiver.config.Region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", receiver.config.AccessKey, scope, signedHeaders, signature))
+
+	rsp, err := receiver.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s：%d %s", method, path, rsp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// escapePath 按S3的规则编码路径（保留/）
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, content string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
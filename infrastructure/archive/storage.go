@@ -0,0 +1,74 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	storageLocal = "local" // 本地目录
+	storageS3    = "s3"    // S3兼容的对象存储
+)
+
+// storage 归档文件的存储
+type storage interface {
+	// put 保存文件
+	put(key string, content []byte) error
+	// get 读取文件
+	get(key string) ([]byte, error)
+	// list 列出前缀下的文件
+	list(prefix string) ([]objectVO, error)
+}
+
+// objectVO 存储中的文件
+type objectVO struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// localStorage 本地目录
+type localStorage struct {
+	root string
+}
+
+func (receiver *localStorage) put(key string, content []byte) error {
+	path := filepath.Join(receiver.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免中断时留下不完整的归档
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (receiver *localStorage) get(key string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(receiver.root, filepath.FromSlash(key)))
+}
+
+func (receiver *localStorage) list(prefix string) ([]objectVO, error) {
+	var lst []objectVO
+	dir := filepath.Join(receiver.root, filepath.FromSlash(prefix))
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		rel, _ := filepath.Rel(receiver.root, path)
+		lst = append(lst, objectVO{Key: filepath.ToSlash(rel), Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	sort.Slice(lst, func(i, j int) bool { return lst[i].Key < lst[j].Key })
+	return lst, err
+}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"FSchedule/domain/client"
+	"FSchedule/domain/tracing"
+	"errors"
 	"fmt"
 	"github.com/farseer-go/fs/configure"
 	"github.com/farseer-go/fs/core"
@@ -18,69 +20,96 @@ type clientHttp struct {
 
 func (receiver clientHttp) Check(do *client.DomainObject) (client.ResourceVO, error) {
 	clientUrl := fmt.Sprintf("http://%s:%d/api/check", do.Ip, do.Port)
+	span := startSpan("", "Check", do, clientUrl)
+	defer span.End()
 	body := map[string]any{
 		"clientId": do.Id,
 	}
 	var apiResponse core.ApiResponse[client.ResourceVO]
-	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).Body(body).PostUnmarshal(&apiResponse)
+	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).HeadAdd(tracing.HeaderName, span.TraceParent()).Body(body).PostUnmarshal(&apiResponse)
 	if err != nil {
+		span.Error(err)
 		flog.Warningf("客户端（%d）：%s:%d  检查失败", do.Id, do.Ip, do.Port)
 		return client.ResourceVO{}, err
 	}
 	if apiResponse.StatusCode != 200 {
 		log := fmt.Sprintf("客户端（%d）：%s，状态码：%d，错误内容：%s", do.Id, clientUrl, apiResponse.StatusCode, apiResponse.StatusMessage)
-		return client.ResourceVO{}, flog.Error(log)
+		err = flog.Error(log)
+		span.Error(err)
+		return client.ResourceVO{}, err
 	}
 	return apiResponse.Data, nil
 }
 
 func (receiver clientHttp) Invoke(do *client.DomainObject, task *client.TaskEO) (client.ResourceVO, error) {
 	clientUrl := fmt.Sprintf("http://%s:%d/api/invoke", do.Ip, do.Port)
+	span := startSpan(task.TraceParent, "Invoke", do, clientUrl, tracing.Task(task.Name, task.Id)...)
+	defer span.End()
+	// 客户端执行及上报时，沿用本次调用的链路
+	task.TraceParent = span.TraceParent()
 	var apiResponse core.ApiResponse[client.ResourceVO]
-	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).Body(task).PostUnmarshal(&apiResponse)
+	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).HeadAdd(tracing.HeaderName, task.TraceParent).Body(task).PostUnmarshal(&apiResponse)
 	if err != nil {
+		span.Error(err)
 		return client.ResourceVO{}, err
 	}
 	if apiResponse.StatusCode != 200 {
 		log := fmt.Sprintf("客户端（%d）：%s，状态码：%d，错误内容：%s", do.Id, clientUrl, apiResponse.StatusCode, apiResponse.StatusMessage)
 		flog.Info(log)
-		return client.ResourceVO{}, flog.Error(log)
+		err = flog.Error(log)
+		span.Error(err)
+		return client.ResourceVO{}, err
 	}
 	return apiResponse.Data, nil
 }
 
 func (receiver clientHttp) Status(do *client.DomainObject, taskId int64) (client.TaskReportVO, error) {
 	clientUrl := fmt.Sprintf("http://%s:%d/api/status", do.Ip, do.Port)
+	span := startSpan("", "Status", do, clientUrl, tracing.Int64("fschedule.task_id", taskId))
+	defer span.End()
 	var apiResponse core.ApiResponse[client.TaskReportVO]
 	body := map[string]any{
 		"TaskId": taskId,
 	}
-	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).Body(body).PostUnmarshal(&apiResponse)
+	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).HeadAdd(tracing.HeaderName, span.TraceParent()).Body(body).PostUnmarshal(&apiResponse)
 	if err != nil {
+		span.Error(err)
 		return client.TaskReportVO{}, err
 	}
 	if apiResponse.StatusCode != 200 {
 		log := fmt.Sprintf("客户端（%d）：%s，状态码：%d，错误内容：%s", do.Id, clientUrl, apiResponse.StatusCode, apiResponse.StatusMessage)
 		flog.Info(log)
-		return client.TaskReportVO{}, flog.Error(log)
+		err = flog.Error(log)
+		span.Error(err)
+		return client.TaskReportVO{}, err
 	}
 	return apiResponse.Data, nil
 }
 
 func (receiver clientHttp) Kill(do *client.DomainObject, taskId int64) bool {
 	clientUrl := fmt.Sprintf("http://%s:%d/api/kill", do.Ip, do.Port)
+	span := startSpan("", "Kill", do, clientUrl, tracing.Int64("fschedule.task_id", taskId))
+	defer span.End()
 	var apiResponse core.ApiResponse[any]
 	body := map[string]any{
 		"TaskId": taskId,
 	}
-	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).Body(body).PostUnmarshal(&apiResponse)
+	err := http.NewClient(clientUrl).HeadAdd(tokenName, token).HeadAdd(tracing.HeaderName, span.TraceParent()).Body(body).PostUnmarshal(&apiResponse)
 	if err != nil {
+		span.Error(err)
 		return false
 	}
 	if apiResponse.StatusCode != 200 {
 		log := fmt.Sprintf("客户端（%d）：%s，状态码：%d，错误内容：%s", do.Id, clientUrl, apiResponse.StatusCode, apiResponse.StatusMessage)
 		flog.Info(log)
+		span.Error(errors.New(log))
 		return false
 	}
 	return true
 }
+
+// startSpan 请求客户端的Span
+func startSpan(traceParent string, method string, do *client.DomainObject, clientUrl string, attrs ...tracing.Attribute) tracing.Span {
+	attrs = append(attrs, tracing.Int64("fschedule.client_id", do.Id), tracing.String("http.url", clientUrl))
+	return tracing.Start(traceParent, "IClientCheck."+method, attrs...)
+}
@@ -0,0 +1,124 @@
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/flog"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 没有事件时，定时发送心跳，避免被代理断开
+const heartbeatInterval = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: checkOrigin,
+}
+
+// 只允许同源，或FSchedule.Server.AllowedOrigins（逗号分隔）中的来源建立WebSocket（非浏览器的请求没有Origin）
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if originUrl, err := url.Parse(origin); err == nil && strings.EqualFold(originUrl.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range strings.Split(configure.GetString("FSchedule.Server.AllowedOrigins"), ",") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	flog.Warningf("拒绝来源：%s 订阅实时日志", origin)
+	return false
+}
+
+// Connect 订阅任务组（或任务）的日志和状态变更，连接断开前不会返回
+// 请求头带有Upgrade: websocket时使用WebSocket，否则使用Server-Sent Events
+func Connect(w http.ResponseWriter, r *http.Request, name string, taskId int64) {
+	if websocket.IsWebSocketUpgrade(r) {
+		connectWebSocket(w, r, name, taskId)
+		return
+	}
+	connectSSE(w, r, name, taskId)
+}
+
+func connectSSE(w http.ResponseWriter, r *http.Request, name string, taskId int64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "不支持Server-Sent Events", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sub := defaultHub.subscribe(name, taskId)
+	defer defaultHub.unsubscribe(sub)
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event := <-sub.events:
+			data, _ := json.Marshal(event)
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type.String(), data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func connectWebSocket(w http.ResponseWriter, r *http.Request, name string, taskId int64) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		_ = flog.Errorf("订阅实时日志建立长连接失败：%s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	sub := defaultHub.subscribe(name, taskId)
+	defer defaultHub.unsubscribe(sub)
+
+	// 只推送，不接收订阅者的消息。读取是为了感知连接断开
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case event := <-sub.events:
+			if err = conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
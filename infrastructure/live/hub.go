@@ -0,0 +1,104 @@
+package live
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskLive"
+	"github.com/farseer-go/fs/container"
+	"sync"
+)
+
+// 每个订阅者最多缓存的事件数量，超过后丢弃，避免慢连接阻塞日志上报
+const bufferSize = 256
+
+// hub 当前节点的实时订阅者
+type hub struct {
+	lock        sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	lastStatus  map[string]taskLive.EventVO // 每个任务组最后一次推送的状态
+}
+
+// subscriber 订阅者
+type subscriber struct {
+	name   string                // 订阅的任务组（为空时订阅所有）
+	taskId int64                 // 订阅的任务（为0时订阅所有）
+	events chan taskLive.EventVO // 待推送的事件
+}
+
+var defaultHub = &hub{
+	subscribers: make(map[*subscriber]struct{}),
+	lastStatus:  make(map[string]taskLive.EventVO),
+}
+
+func (receiver *hub) Push(events ...taskLive.EventVO) {
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+	if len(receiver.subscribers) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		if event.Type == enum.LiveStatus {
+			if last, exists := receiver.lastStatus[event.Name]; exists && event.IsSameStatus(last) {
+				continue
+			}
+			receiver.lastStatus[event.Name] = event
+		}
+
+		for sub := range receiver.subscribers {
+			if !event.IsMatch(sub.name, sub.taskId) {
+				continue
+			}
+			select {
+			case sub.events <- event:
+			default:
+			}
+		}
+	}
+}
+
+// subscribe 添加订阅者
+func (receiver *hub) subscribe(name string, taskId int64) *subscriber {
+	sub := &subscriber{
+		name:   name,
+		taskId: taskId,
+		events: make(chan taskLive.EventVO, bufferSize),
+	}
+	receiver.lock.Lock()
+	receiver.subscribers[sub] = struct{}{}
+	receiver.lock.Unlock()
+	// 记录到集群，有订阅者时日志上报才推送
+	container.Resolve[taskLive.Repository]().Subscribe([]string{name})
+	return sub
+}
+
+// unsubscribe 移除订阅者
+func (receiver *hub) unsubscribe(sub *subscriber) {
+	receiver.lock.Lock()
+	delete(receiver.subscribers, sub)
+	// 没有订阅者时，不再记录状态，下次订阅时重新推送
+	if len(receiver.subscribers) == 0 {
+		receiver.lastStatus = make(map[string]taskLive.EventVO)
+	}
+	receiver.lock.Unlock()
+}
+
+func (receiver *hub) Names() []string {
+	receiver.lock.RLock()
+	defer receiver.lock.RUnlock()
+	exists := make(map[string]bool)
+	var names []string
+	for sub := range receiver.subscribers {
+		if !exists[sub.name] {
+			exists[sub.name] = true
+			names = append(names, sub.name)
+		}
+	}
+	return names
+}
+
+// SubscriberCount 当前节点的订阅者数量
+func SubscriberCount() int {
+	defaultHub.lock.RLock()
+	defer defaultHub.lock.RUnlock()
+	return len(defaultHub.subscribers)
+}
@@ -0,0 +1,25 @@
+package live
+
+import (
+	"FSchedule/domain/taskLive"
+	"github.com/farseer-go/fs/container"
+	"time"
+)
+
+// 定时刷新当前节点的订阅记录（需小于订阅记录的有效期）
+const refreshInterval = 10 * time.Second
+
+// InitLive 初始化实时订阅
+func InitLive() {
+	container.Register(func() taskLive.IHub {
+		return defaultHub
+	})
+
+	go func() {
+		for range time.Tick(refreshInterval) {
+			if names := defaultHub.Names(); len(names) > 0 {
+				container.Resolve[taskLive.Repository]().Subscribe(names)
+			}
+		}
+	}()
+}
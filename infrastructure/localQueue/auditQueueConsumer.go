@@ -0,0 +1,16 @@
+package localQueue
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/infrastructure/repository"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/container"
+)
+
+// AuditQueueConsumer 将审计记录写入
+func AuditQueueConsumer(subscribeName string, message collections.ListAny, remainingCount int) {
+	var lstPO collections.List[model.AuditPO]
+	message.MapToList(&lstPO)
+	container.Resolve[audit.Repository]().(*repository.AuditRepository).AddBatch(lstPO)
+}
@@ -0,0 +1,16 @@
+package localQueue
+
+import (
+	"FSchedule/domain/taskEvent"
+	"FSchedule/infrastructure/repository"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/container"
+)
+
+// TaskEventQueueConsumer 将任务的状态变更记录写入
+func TaskEventQueueConsumer(subscribeName string, message collections.ListAny, remainingCount int) {
+	var lstPO collections.List[model.TaskEventPO]
+	message.MapToList(&lstPO)
+	container.Resolve[taskEvent.Repository]().(*repository.TaskEventRepository).AddBatch(lstPO)
+}
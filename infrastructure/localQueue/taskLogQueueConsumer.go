@@ -2,10 +2,12 @@ package localQueue
 
 import (
 	"FSchedule/domain/taskLog"
+	"FSchedule/infrastructure/logSink"
 	"FSchedule/infrastructure/repository"
 	"FSchedule/infrastructure/repository/model"
 	"github.com/farseer-go/collections"
 	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/mapper"
 )
 
 // TaskLogQueueConsumer 将日志指写入
@@ -13,5 +15,7 @@ func TaskLogQueueConsumer(subscribeName string, message collections.ListAny, rem
 	// 转成BuildLogVO数组
 	var lstPO collections.List[model.TaskLogPO]
 	message.MapToList(&lstPO)
+	// 先输出到Sink，数据库写入失败时不影响
+	logSink.Push(mapper.ToList[taskLog.DomainObject](lstPO))
 	container.Resolve[taskLog.Repository]().(*repository.TaskLogRepository).AddBatch(lstPO)
 }
@@ -0,0 +1,47 @@
+package logSink
+
+// sinkConfig Sink的配置（FSchedule.LogSink.{名称}）
+type sinkConfig struct {
+	Type          string // 类型：elasticsearch、loki、file
+	Url           string // 服务地址（elasticsearch、loki）
+	Index         string // 索引名称前缀，按天创建索引（elasticsearch，默认fschedule_task_log）
+	Username      string // Basic认证的用户名
+	Password      string // Basic认证的密码
+	Path          string // 日志文件的目录（file，默认./log）
+	MaxSize       int    // 单个日志文件的大小，超过后滚动（file，单位MB，默认100）
+	MaxFiles      int    // 保留滚动后的文件数量（file，默认10）
+	BatchSize     int    // 每批写入的日志数量（默认500）
+	FlushInterval int    // 未满一批时，最长等待多久写入（单位毫秒，默认1000）
+	QueueSize     int    // 等待写入的日志数量上限，超过后丢弃（默认10000）
+	MaxRetry      int    // 写入失败时的重试次数（默认3，-1不重试）
+}
+
+// 未配置的项使用默认值
+func (receiver *sinkConfig) setDefault() {
+	if receiver.Index == "" {
+		receiver.Index = "fschedule_task_log"
+	}
+	if receiver.Path == "" {
+		receiver.Path = "./log"
+	}
+	if receiver.MaxSize <= 0 {
+		receiver.MaxSize = 100
+	}
+	if receiver.MaxFiles <= 0 {
+		receiver.MaxFiles = 10
+	}
+	if receiver.BatchSize <= 0 {
+		receiver.BatchSize = 500
+	}
+	if receiver.FlushInterval <= 0 {
+		receiver.FlushInterval = 1000
+	}
+	if receiver.QueueSize <= 0 {
+		receiver.QueueSize = 10000
+	}
+	if receiver.MaxRetry < 0 {
+		receiver.MaxRetry = 0
+	} else if receiver.MaxRetry == 0 {
+		receiver.MaxRetry = 3
+	}
+}
@@ -0,0 +1,64 @@
+package logSink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/farseer-go/fs/flog"
+	"strings"
+)
+
+// elasticsearchSink 通过bulk接口写入elasticsearch，按天创建索引
+type elasticsearchSink struct {
+	config sinkConfig
+	url    string
+}
+
+func newElasticsearchSink(config sinkConfig) (Sink, error) {
+	if config.Url == "" {
+		return nil, errors.New("缺少Url配置")
+	}
+	return &elasticsearchSink{
+		config: config,
+		url:    strings.TrimSuffix(config.Url, "/") + "/_bulk",
+	}, nil
+}
+
+func (receiver *elasticsearchSink) Write(lst []LogVO) error {
+	var buf bytes.Buffer
+	for _, vo := range lst {
+		action, _ := json.Marshal(map[string]any{"index": map[string]string{"_index": receiver.config.Index + "-" + vo.CreateAt.Format("2006.01.02")}})
+		doc, _ := json.Marshal(vo)
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+	rspBody, err := post(receiver.config, receiver.url, "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	// 部分文档写入失败时不重试，避免重复写入已成功的文档
+	var rsp struct {
+		Errors bool
+		Items  []map[string]struct {
+			Status int
+		}
+	}
+	if json.Unmarshal(rspBody, &rsp) == nil && rsp.Errors {
+		failCount := 0
+		for _, item := range rsp.Items {
+			for _, result := range item {
+				if result.Status >= 300 {
+					failCount++
+				}
+			}
+		}
+		flog.Warningf("日志Sink：elasticsearch 有%d条日志写入失败", failCount)
+	}
+	return nil
+}
+
+func (receiver *elasticsearchSink) Close() {
+}
@@ -0,0 +1,93 @@
+package logSink
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 当前写入的文件名，滚动后重命名为task_log-{时间}.jsonl
+const fileName = "task_log.jsonl"
+
+// fileSink 写入本地的JSON Lines文件，超过大小后滚动
+type fileSink struct {
+	config sinkConfig
+	file   *os.File
+	size   int64
+}
+
+func newFileSink(config sinkConfig) (Sink, error) {
+	if err := os.MkdirAll(config.Path, 0755); err != nil {
+		return nil, err
+	}
+	sink := &fileSink{config: config}
+	return sink, sink.open()
+}
+
+func (receiver *fileSink) open() error {
+	file, err := os.OpenFile(filepath.Join(receiver.config.Path, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	receiver.file = file
+	receiver.size = stat.Size()
+	return nil
+}
+
+func (receiver *fileSink) Write(lst []LogVO) error {
+	if receiver.file == nil {
+		if err := receiver.open(); err != nil {
+			return err
+		}
+	}
+
+	writer := bufio.NewWriter(receiver.file)
+	for _, vo := range lst {
+		line, _ := json.Marshal(vo)
+		n, _ := writer.Write(line)
+		_ = writer.WriteByte('\n')
+		receiver.size += int64(n + 1)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if receiver.size >= int64(receiver.config.MaxSize)*1024*1024 {
+		receiver.rotate()
+	}
+	return nil
+}
+
+// rotate 滚动文件，并删除超出数量的旧文件
+func (receiver *fileSink) rotate() {
+	_ = receiver.file.Close()
+	receiver.file = nil
+	name := "task_log-" + time.Now().Format("20060102-150405.000") + ".jsonl"
+	_ = os.Rename(filepath.Join(receiver.config.Path, fileName), filepath.Join(receiver.config.Path, name))
+
+	matches, _ := filepath.Glob(filepath.Join(receiver.config.Path, "task_log-*.jsonl"))
+	if len(matches) <= receiver.config.MaxFiles {
+		return
+	}
+	sort.Strings(matches)
+	for _, match := range matches[:len(matches)-receiver.config.MaxFiles] {
+		if strings.HasSuffix(match, ".jsonl") {
+			_ = os.Remove(match)
+		}
+	}
+}
+
+func (receiver *fileSink) Close() {
+	if receiver.file != nil {
+		_ = receiver.file.Close()
+	}
+}
@@ -0,0 +1,36 @@
+package logSink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// 写入elasticsearch、loki的超时时间
+const httpTimeout = 10 * time.Second
+
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// post 发送到elasticsearch、loki，非2xx时返回错误
+func post(config sinkConfig, url string, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if config.Username != "" {
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+	rsp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	rspBody, _ := io.ReadAll(rsp.Body)
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%d %s", rsp.StatusCode, string(rspBody))
+	}
+	return rspBody, nil
+}
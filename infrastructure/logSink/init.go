@@ -0,0 +1,71 @@
+package logSink
+
+import (
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"strings"
+)
+
+// 已启用的Sink
+var workers []*worker
+
+// InitLogSink 根据配置（FSchedule.LogSink）启用Sink
+func InitLogSink() {
+	for name, node := range configure.GetSubNodes("FSchedule.LogSink") {
+		// 支持连接字符串（Type=file,Path=./log）、yaml子节点两种配置方式
+		var config sinkConfig
+		if str, isString := node.(string); isString {
+			config = configure.ParseString[sinkConfig](str)
+		} else {
+			config = configure.ParseConfig[sinkConfig]("FSchedule.LogSink." + name)
+		}
+		config.setDefault()
+		factory, exists := factories[strings.ToLower(config.Type)]
+		if !exists {
+			_ = flog.Errorf("日志Sink：%s 不支持的类型：%s", name, config.Type)
+			continue
+		}
+		sink, err := factory(config)
+		if err != nil {
+			_ = flog.Errorf("日志Sink：%s 初始化失败：%s", name, err.Error())
+			continue
+		}
+		workers = append(workers, newWorker(name, sink, config))
+		flog.Infof("日志Sink：%s（%s）已启用", name, config.Type)
+	}
+}
+
+// Push 日志放入每个Sink的队列（不阻塞）
+func Push(lst collections.List[taskLog.DomainObject]) {
+	if len(workers) == 0 {
+		return
+	}
+	// 按各任务组的参数定义脱敏
+	taskGroupRepository := container.Resolve[taskGroup.Repository]()
+	secretNames := make(map[string][]string)
+	lstVO := make([]LogVO, 0, lst.Count())
+	for _, do := range lst.ToArray() {
+		names, exists := secretNames[do.Name]
+		if !exists {
+			taskGroupDO := taskGroupRepository.ToEntity(do.Name)
+			names = taskGroupDO.DataSchema.SecretNames()
+			secretNames[do.Name] = names
+		}
+		lstVO = append(lstVO, newLogVO(do, names))
+	}
+	for _, w := range workers {
+		w.push(lstVO)
+	}
+}
+
+// Close 写入队列中剩余的日志后停止
+func Close() {
+	for _, w := range workers {
+		w.close()
+	}
+	workers = nil
+}
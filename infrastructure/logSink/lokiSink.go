@@ -0,0 +1,58 @@
+package logSink
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// lokiSink 通过push接口写入loki，以任务组名称、日志级别作为标签
+type lokiSink struct {
+	config sinkConfig
+	url    string
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func newLokiSink(config sinkConfig) (Sink, error) {
+	if config.Url == "" {
+		return nil, errors.New("缺少Url配置")
+	}
+	return &lokiSink{
+		config: config,
+		url:    strings.TrimSuffix(config.Url, "/") + "/loki/api/v1/push",
+	}, nil
+}
+
+func (receiver *lokiSink) Write(lst []LogVO) error {
+	streams := make(map[string]*lokiStream)
+	var keys []string
+	for _, vo := range lst {
+		key := vo.Name + "|" + vo.Level
+		stream, exists := streams[key]
+		if !exists {
+			stream = &lokiStream{Stream: map[string]string{"job": "fschedule", "name": vo.Name, "level": vo.Level, "host": vo.Host}}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		line, _ := json.Marshal(map[string]any{"taskId": vo.TaskId, "ver": vo.Ver, "content": vo.Content})
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(vo.CreateAt.UnixNano(), 10), string(line)})
+	}
+
+	body := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range keys {
+		body.Streams = append(body.Streams, streams[key])
+	}
+	marshal, _ := json.Marshal(body)
+	_, err := post(receiver.config, receiver.url, "application/json", marshal)
+	return err
+}
+
+func (receiver *lokiSink) Close() {
+}
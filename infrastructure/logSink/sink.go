@@ -0,0 +1,57 @@
+package logSink
+
+import (
+	"FSchedule/domain/secret"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/core/eumLogLevel"
+	"time"
+)
+
+// Sink 任务日志的输出目的地（数据库之外）
+type Sink interface {
+	// Write 批量写入，返回错误时会重试
+	Write(lst []LogVO) error
+	// Close 停止时释放资源
+	Close()
+}
+
+// 根据配置创建Sink
+var factories = map[string]func(config sinkConfig) (Sink, error){
+	"elasticsearch": newElasticsearchSink,
+	"loki":          newLokiSink,
+	"file":          newFileSink,
+}
+
+// LogVO 输出到Sink的日志
+type LogVO struct {
+	Name     string            // 任务组名称
+	Caption  string            // 任务组标题
+	Ver      int               // 版本
+	TaskId   int64             // 任务ID
+	Data     map[string]string // 本次执行任务时的Data数据
+	LogLevel eumLogLevel.Enum  // 日志级别
+	Level    string            // 日志级别名称
+	Content  string            // 日志内容
+	CreateAt time.Time         // 日志时间
+	Host     string            // 接收日志的服务端节点
+}
+
+// names：Secret参数的名称（输出前脱敏）
+func newLogVO(do taskLog.DomainObject, names []string) LogVO {
+	vo := LogVO{
+		Name:     do.Name,
+		Caption:  do.Caption,
+		Ver:      do.Ver,
+		TaskId:   do.TaskId,
+		LogLevel: do.LogLevel,
+		Level:    eumLogLevel.GetName(do.LogLevel),
+		Content:  do.Content,
+		CreateAt: do.CreateAt,
+		Host:     fs.HostName,
+	}
+	if do.Data.Count() > 0 {
+		vo.Data = secret.MaskData(do.Data, names...).ToMap()
+	}
+	return vo
+}
@@ -0,0 +1,122 @@
+package logSink
+
+import (
+	"github.com/farseer-go/fs/flog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 丢弃日志时，最多每隔多久打印一次警告
+const dropWarnInterval = time.Minute
+
+// worker 每个Sink独立的队列，批量写入、失败重试。队列满时丢弃，不阻塞日志上报
+type worker struct {
+	name    string
+	sink    Sink
+	config  sinkConfig
+	queue   chan LogVO
+	dropped int64 // 队列满或重试失败丢弃的日志数量
+	stop    chan struct{}
+	done    sync.WaitGroup
+}
+
+func newWorker(name string, sink Sink, config sinkConfig) *worker {
+	w := &worker{
+		name:   name,
+		sink:   sink,
+		config: config,
+		queue:  make(chan LogVO, config.QueueSize),
+		stop:   make(chan struct{}),
+	}
+	w.done.Add(1)
+	go w.run()
+	return w
+}
+
+// push 放入队列，队列满时丢弃
+func (receiver *worker) push(lst []LogVO) {
+	for _, vo := range lst {
+		select {
+		case receiver.queue <- vo:
+		default:
+			atomic.AddInt64(&receiver.dropped, 1)
+		}
+	}
+}
+
+func (receiver *worker) run() {
+	defer receiver.done.Done()
+	ticker := time.NewTicker(time.Duration(receiver.config.FlushInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]LogVO, 0, receiver.config.BatchSize)
+	lastWarnAt := time.Now()
+	for {
+		select {
+		case vo := <-receiver.queue:
+			batch = append(batch, vo)
+			if len(batch) < receiver.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if dropped := atomic.SwapInt64(&receiver.dropped, 0); dropped > 0 {
+				if time.Since(lastWarnAt) >= dropWarnInterval {
+					flog.Warningf("日志Sink：%s 处理不过来，丢弃了%d条日志", receiver.name, dropped)
+					lastWarnAt = time.Now()
+				} else {
+					atomic.AddInt64(&receiver.dropped, dropped)
+				}
+			}
+		case <-receiver.stop:
+			// 停止前写入队列中剩余的日志
+			for len(receiver.queue) > 0 {
+				batch = append(batch, <-receiver.queue)
+				if len(batch) >= receiver.config.BatchSize {
+					receiver.write(batch)
+					batch = batch[:0]
+				}
+			}
+			receiver.write(batch)
+			return
+		}
+
+		if len(batch) > 0 {
+			receiver.write(batch)
+			batch = make([]LogVO, 0, receiver.config.BatchSize)
+		}
+	}
+}
+
+// write 写入一批日志，失败时按1s、2s、4s...的间隔重试
+func (receiver *worker) write(batch []LogVO) {
+	if len(batch) == 0 {
+		return
+	}
+	backoff := time.Second
+	for retry := 0; ; retry++ {
+		err := receiver.sink.Write(batch)
+		if err == nil {
+			return
+		}
+		if retry >= receiver.config.MaxRetry {
+			_ = flog.Errorf("日志Sink：%s 写入失败，丢弃%d条日志：%s", receiver.name, len(batch), err.Error())
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-receiver.stop:
+			// 停止时不再等待
+			_ = flog.Errorf("日志Sink：%s 写入失败，丢弃%d条日志：%s", receiver.name, len(batch), err.Error())
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// close 写入剩余的日志后停止
+func (receiver *worker) close() {
+	close(receiver.stop)
+	receiver.done.Wait()
+	receiver.sink.Close()
+}
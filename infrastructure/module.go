@@ -1,11 +1,18 @@
 package infrastructure
 
 import (
+	"FSchedule/application/clusterApp"
 	"FSchedule/application/domainEvent"
 	"FSchedule/domain/serverNode"
+	"FSchedule/infrastructure/archive"
 	"FSchedule/infrastructure/http"
+	"FSchedule/infrastructure/live"
 	"FSchedule/infrastructure/localQueue"
+	"FSchedule/infrastructure/logSink"
 	"FSchedule/infrastructure/repository"
+	"FSchedule/infrastructure/secret"
+	"FSchedule/infrastructure/stream"
+	"FSchedule/infrastructure/tracing"
 	"github.com/farseer-go/data"
 	"github.com/farseer-go/eventBus"
 	"github.com/farseer-go/fs"
@@ -31,9 +38,15 @@ func (module Module) Initialize() {
 
 func (module Module) PostInitialize() {
 	timingWheel.Start()
+	// 链路追踪
+	tracing.InitTracing()
 
 	// 注册仓储
 	repository.InitRepository()
+	// 注册归档仓储
+	archive.InitArchive()
+	// 注册Secret参数的加密方式
+	secret.InitSecret()
 
 	// 注册任务组更新通知事件
 	redis.RegisterEvent("default", "TaskGroupUpdate", domainEvent.TaskGroupUpdateSubscribe)
@@ -44,17 +57,42 @@ func (module Module) PostInitialize() {
 	eventBus.RegisterEvent("CheckWorking", domainEvent.CheckWorkingEvent)
 	// 任务完成事件
 	eventBus.RegisterEvent("TaskFinish", domainEvent.TaskFinishEvent)
+	// 任务执行中到达下一个执行周期
+	eventBus.RegisterEvent("TaskOverlap", domainEvent.TaskOverlapEvent)
+	// 任务状态变更
+	eventBus.RegisterEvent("TaskTransit", domainEvent.TaskTransitEvent, domainEvent.TaskWorkingEvent)
 
 	// 注册客户端更新通知事件
 	redis.RegisterEvent("default", "ClientUpdate", domainEvent.ClientUpdateSubscribe)
+	// 注册任务实时日志事件
+	redis.RegisterEvent("default", "TaskLive", domainEvent.TaskLiveSubscribe)
 	// 注册选举事件
 	redis.RegisterEvent("default", "ClusterLeader", domainEvent.ClusterLeaderSubscribe)
+	// 注册Master让位事件
+	redis.RegisterEvent("default", "ClusterStepDown", domainEvent.ClusterStepDownSubscribe)
+	// 注册节点释放调度锁事件
+	redis.RegisterEvent("default", "ScheduleRelease", domainEvent.ScheduleReleaseSubscribe)
+	// 注册任务组重新分配事件
+	redis.RegisterEvent("default", "ScheduleAssign", domainEvent.ScheduleAssignSubscribe)
 
+	// 任务日志输出到数据库之外的Sink
+	logSink.InitLogSink()
 	// 队列任务日志
 	queue.Subscribe("TaskLogQueue", "", 1000, localQueue.TaskLogQueueConsumer)
+	// 队列审计记录
+	queue.Subscribe("AuditQueue", "", 100, localQueue.AuditQueueConsumer)
+	// 队列任务状态变更记录
+	queue.Subscribe("TaskEventQueue", "", 1000, localQueue.TaskEventQueueConsumer)
 
 	// 注册客户端http
 	http.InitHttp()
+	// 注册客户端长连接
+	stream.InitStream()
+	// 注册实时订阅
+	live.InitLive()
+
+	// 日志内容的全文索引
+	fs.AddInitCallback("创建日志全文索引", repository.InitTaskLogFullText)
 
 	fs.AddInitCallback("注册节点信息", func() {
 		container.Resolve[serverNode.Repository]().Save(serverNode.New())
@@ -62,4 +100,8 @@ func (module Module) PostInitialize() {
 }
 
 func (module Module) Shutdown() {
+	// 基础设施模块先于应用层关闭，需在关闭日志、链路前交接任务组
+	clusterApp.Shutdown()
+	logSink.Close()
+	tracing.Close()
 }
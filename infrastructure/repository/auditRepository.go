@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/data"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/mapper"
+	"github.com/farseer-go/queue"
+)
+
+type AuditRepository struct {
+	Audit data.TableSet[model.AuditPO] `data:"name=fschedule_audit"`
+}
+
+func (repository *AuditRepository) Add(do audit.DomainObject) {
+	po := mapper.Single[model.AuditPO](do)
+	queue.Push("AuditQueue", po)
+}
+
+func (repository *AuditRepository) ToPageList(filter audit.FilterVO, pageSize int, pageIndex int) collections.PageList[audit.DomainObject] {
+	ts := &repository.Audit
+	if len(filter.Types) > 0 {
+		ts = ts.Where("type in ?", filter.Types)
+	}
+	if filter.Actor != "" {
+		ts = ts.Where("actor = ?", filter.Actor)
+	}
+	if filter.Ip != "" {
+		ts = ts.Where("ip = ?", filter.Ip)
+	}
+	if filter.Target != "" {
+		ts = ts.Where("target = ?", filter.Target)
+	}
+	if !filter.StartAt.IsZero() {
+		ts = ts.Where("create_at >= ?", filter.StartAt)
+	}
+	if !filter.EndAt.IsZero() {
+		ts = ts.Where("create_at < ?", filter.EndAt)
+	}
+	page := ts.Desc("create_at").ToPageList(pageSize, pageIndex)
+
+	var pageList collections.PageList[audit.DomainObject]
+	page.MapToPageList(&pageList)
+	return pageList
+}
+
+func (repository *AuditRepository) AddBatch(lstPO collections.List[model.AuditPO]) {
+	err := repository.Audit.InsertList(lstPO, 50)
+	if err != nil {
+		exception.ThrowRefuseException("批量添加报错")
+	}
+}
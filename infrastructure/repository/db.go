@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"github.com/farseer-go/fs/configure"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 与仓储的data上下文使用同一个数据库配置
+const dbName = "default"
+
+// 与data组件的数据库配置格式一致
+type dbConfig struct {
+	DataType         string
+	PoolMaxSize      int
+	ConnectionString string
+}
+
+var sharedDb struct {
+	lock sync.Mutex
+	db   *gorm.DB
+}
+
+// openDb 按data上下文的配置（Database.default）打开数据库连接，进程内共享一个连接池（data组件不提供原生SQL，用于DDL、健康检查）
+// 不支持的数据库类型返回nil
+func openDb() (*gorm.DB, string, error) {
+	config := configure.ParseString[dbConfig](configure.GetString("Database." + dbName))
+	dataType := strings.ToLower(config.DataType)
+	var dialector gorm.Dialector
+	switch dataType {
+	case "mysql":
+		dialector = mysql.Open(config.ConnectionString)
+	case "postgresql":
+		dialector = postgres.Open(config.ConnectionString)
+	case "sqlite":
+		dialector = sqlite.Open(config.ConnectionString)
+	default:
+		return nil, dataType, nil
+	}
+
+	sharedDb.lock.Lock()
+	defer sharedDb.lock.Unlock()
+	// 连接失败时不缓存，下次重新连接
+	if sharedDb.db == nil {
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), SkipDefaultTransaction: true})
+		if err != nil {
+			return nil, dataType, err
+		}
+		// 只用于DDL、健康检查，保持少量连接（不超过data组件的连接池大小）
+		if sqlDB, err := db.DB(); err == nil {
+			maxSize := 2
+			if config.PoolMaxSize > 0 && config.PoolMaxSize < maxSize {
+				maxSize = config.PoolMaxSize
+			}
+			sqlDB.SetMaxOpenConns(maxSize)
+			sqlDB.SetMaxIdleConns(1)
+			sqlDB.SetConnMaxLifetime(time.Hour)
+		}
+		sharedDb.db = db
+	}
+	return sharedDb.db, dataType, nil
+}
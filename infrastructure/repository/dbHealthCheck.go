@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// dbHealthCheck 检查数据库是否可以连接（复用DDL的共享连接，每次检查都会Ping数据库，超时3秒）
+type dbHealthCheck struct {
+	db   *sql.DB
+	lock sync.Mutex
+}
+
+func (receiver *dbHealthCheck) Check() (string, error) {
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+
+	if receiver.db == nil {
+		gormDB, _, err := openDb()
+		if err != nil {
+			return "Database.ping", err
+		}
+		if gormDB == nil {
+			return "Database.ping", nil
+		}
+		if receiver.db, err = gormDB.DB(); err != nil {
+			return "Database.ping", err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return "Database.ping", receiver.db.PingContext(ctx)
+}
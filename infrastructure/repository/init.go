@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"FSchedule/domain/audit"
 	"FSchedule/domain/client"
 	"FSchedule/domain/schedule"
 	"FSchedule/domain/serverNode"
+	"FSchedule/domain/taskEvent"
+	"FSchedule/domain/taskLive"
 	"FSchedule/domain/taskLog"
 	"github.com/farseer-go/data"
 	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
 )
 
 // InitRepository 初始化仓储
@@ -31,5 +35,23 @@ func InitRepository() {
 		return data.NewContext[TaskLogRepository]("default", true)
 	})
 
+	// 注册audit仓储
+	container.Register(func() audit.Repository {
+		return data.NewContext[AuditRepository]("default", true)
+	})
+
+	// 注册taskLive仓储
+	container.Register(func() taskLive.Repository {
+		return &taskLiveRepository{}
+	})
+
+	// 注册taskEvent仓储
+	container.Register(func() taskEvent.Repository {
+		return data.NewContext[TaskEventRepository]("default", true)
+	})
+
 	registerTaskGroupRepository()
+
+	// 就绪检查时访问数据库
+	container.RegisterInstance[core.IHealthCheck](&dbHealthCheck{}, "db_ping")
 }
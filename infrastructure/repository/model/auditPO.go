@@ -0,0 +1,20 @@
+package model
+
+import (
+	"FSchedule/domain/audit"
+	"FSchedule/domain/enum"
+	"time"
+)
+
+type AuditPO struct {
+	Id       int64          `gorm:"primaryKey;comment:主键"`
+	Type     enum.AuditType `gorm:"type:tinyint;not null;index:idx_type_create,priority:1;comment:审计类型"`
+	Action   string         `gorm:"size:64;not null;comment:操作名称"`
+	Actor    string         `gorm:"size:64;not null;index:idx_actor_create,priority:1;comment:操作人"`
+	Ip       string         `gorm:"size:64;not null;comment:来源IP"`
+	Target   string         `gorm:"size:64;not null;index:idx_target_create,priority:1;comment:操作对象"`
+	Before   string         `gorm:"type:text;size:0;not null;comment:变更前"`
+	After    string         `gorm:"type:text;size:0;not null;comment:变更后"`
+	Diff     []audit.DiffVO `gorm:"type:text;size:0;serializer:json;not null;comment:变更的字段"`
+	CreateAt time.Time      `gorm:"type:timestamp;size:6;not null;index:idx_create;index:idx_type_create,priority:2;index:idx_actor_create,priority:2;index:idx_target_create,priority:2;comment:操作时间"`
+}
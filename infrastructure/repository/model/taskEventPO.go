@@ -0,0 +1,17 @@
+package model
+
+import (
+	"FSchedule/domain/enum"
+	"time"
+)
+
+type TaskEventPO struct {
+	Id         int64           `gorm:"primaryKey;comment:主键"`
+	Name       string          `gorm:"size:64;not null;index:idx_name_task,priority:1;comment:任务组名称"`
+	TaskId     int64           `gorm:"type:bigint;not null;index:idx_task;index:idx_name_task,priority:2;comment:任务ID"`
+	FromStatus enum.TaskStatus `gorm:"type:tinyint;not null;comment:变更前的状态"`
+	ToStatus   enum.TaskStatus `gorm:"type:tinyint;not null;comment:变更后的状态"`
+	NodeId     int64           `gorm:"type:bigint;not null;comment:变更的服务端节点"`
+	Reason     string          `gorm:"size:256;not null;comment:变更原因"`
+	CreateAt   time.Time       `gorm:"type:timestamp;size:6;not null;comment:变更时间"`
+}
@@ -1,22 +1,41 @@
 package model
 
 import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
 	"github.com/farseer-go/collections"
 	"time"
 )
 
 type TaskGroupPO struct {
-	Name        string                                 `gorm:"primaryKey;size:64;not null;comment:任务组名称"`
-	Ver         int                                    `gorm:"type:int;not null;comment:版本"`
-	Caption     string                                 `gorm:"size:32;not null;comment:任务组标题"`
-	StartAt     time.Time                              `gorm:"type:timestamp;size:6;not null;comment:开始时间"`
-	NextAt      time.Time                              `gorm:"type:timestamp;size:6;not null;comment:下次执行时间"`
-	Cron        string                                 `gorm:"size:32;not null;comment:时间定时器表达式"`
-	ActivateAt  time.Time                              `gorm:"type:timestamp;size:6;not null;comment:活动时间"`
-	LastRunAt   time.Time                              `gorm:"type:timestamp;size:6;not null;comment:最后一次完成时间"`
-	RunSpeedAvg int64                                  `gorm:"type:bigint;not null;comment:运行平均耗时"`
-	RunCount    int                                    `gorm:"type:int;not null;comment:运行次数"`
-	IsEnable    bool                                   `gorm:"size:1;not null;comment:是否开启"`
-	Data        collections.Dictionary[string, string] `gorm:"type:string;size:2048;serializer:json;not null;comment:传给客户端的参数"`
-	Task        TaskPO                                 `gorm:"type:string;size:4096;serializer:json;not null;comment:任务"`
+	Name              string                                 `gorm:"primaryKey;size:64;not null;comment:任务组名称"`
+	Ver               int                                    `gorm:"type:int;not null;comment:版本"`
+	Caption           string                                 `gorm:"size:32;not null;comment:任务组标题"`
+	StartAt           time.Time                              `gorm:"type:timestamp;size:6;not null;comment:开始时间"`
+	NextAt            time.Time                              `gorm:"type:timestamp;size:6;not null;comment:下次执行时间"`
+	Cron              string                                 `gorm:"size:32;not null;comment:时间定时器表达式"`
+	ActivateAt        time.Time                              `gorm:"type:timestamp;size:6;not null;comment:活动时间"`
+	LastRunAt         time.Time                              `gorm:"type:timestamp;size:6;not null;comment:最后一次完成时间"`
+	RunSpeedAvg       int64                                  `gorm:"type:bigint;not null;comment:运行平均耗时"`
+	RunCount          int                                    `gorm:"type:int;not null;comment:运行次数"`
+	IsEnable          bool                                   `gorm:"size:1;not null;comment:是否开启"`
+	Data              collections.Dictionary[string, string] `gorm:"type:text;size:0;serializer:json;not null;comment:传给客户端的参数"`
+	DataSchema        taskGroup.DataSchemaVO                 `gorm:"type:text;size:0;serializer:json;not null;comment:参数的定义"`
+	Task              TaskPO                                 `gorm:"type:text;size:0;serializer:json;not null;comment:任务"`
+	ConcurrencyPolicy enum.ConcurrencyPolicy                 `gorm:"type:tinyint;not null;default:0;comment:并发策略"`
+	MaxParallel       int                                    `gorm:"type:int;not null;default:0;comment:最多同时执行的任务数量"`
+	RunningTaskIds    []int64                                `gorm:"type:text;size:0;serializer:json;not null;comment:并行执行中的任务"`
+	SkipCount         int                                    `gorm:"type:int;not null;default:0;comment:当前任务执行中跳过的执行周期数量"`
+	SkipAt            time.Time                              `gorm:"type:timestamp;size:6;comment:当前任务执行中第一次跳过的执行周期"`
+	SkipTaskId        int64                                  `gorm:"type:bigint;not null;default:0;comment:当前任务执行中跳过的执行周期合并记录的任务ID"`
+	IsClientNextAt    bool                                   `gorm:"size:1;not null;default:0;comment:下次执行时间由客户端决定"`
+	Priority          int                                    `gorm:"type:int;not null;default:0;comment:优先级"`
+	Namespace         string                                 `gorm:"size:64;not null;comment:命名空间"`
+	RollbackVer       int                                    `gorm:"type:int;not null;default:0;comment:回滚到的版本"`
+	VersionPolicy     enum.VersionPolicy                     `gorm:"type:tinyint;not null;default:0;comment:调度客户端版本的策略"`
+	AllowPrevious     int                                    `gorm:"type:int;not null;default:0;comment:允许调度给前N个版本的客户端"`
+	Canary            taskGroup.CanaryVO                     `gorm:"type:string;size:1024;serializer:json;not null;comment:金丝雀发布"`
+	LogRetention      taskGroup.LogRetentionVO               `gorm:"type:string;size:256;serializer:json;not null;comment:任务日志的保留策略"`
+	Checkpoint        string                                 `gorm:"type:text;size:0;comment:客户端下线时任务保存的断点"`
+	IsImported        bool                                   `gorm:"size:1;not null;default:0;comment:定义由配置导入管理"`
 }
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"FSchedule/domain/enum"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"time"
+)
+
+type TaskGroupVersionPO struct {
+	Name              string                                 `gorm:"primaryKey;size:64;not null;comment:任务组名称"`
+	Ver               int                                    `gorm:"primaryKey;type:int;not null;comment:版本"`
+	Caption           string                                 `gorm:"size:32;not null;comment:任务组标题"`
+	Cron              string                                 `gorm:"size:32;not null;comment:时间定时器表达式"`
+	StartAt           time.Time                              `gorm:"type:timestamp;size:6;not null;comment:开始时间"`
+	IsEnable          bool                                   `gorm:"size:1;not null;comment:是否开启"`
+	Data              collections.Dictionary[string, string] `gorm:"type:text;size:0;serializer:json;not null;comment:传给客户端的参数"`
+	DataSchema        taskGroup.DataSchemaVO                 `gorm:"type:text;size:0;serializer:json;not null;comment:参数的定义"`
+	ConcurrencyPolicy enum.ConcurrencyPolicy                 `gorm:"type:tinyint;not null;default:0;comment:并发策略"`
+	MaxParallel       int                                    `gorm:"type:int;not null;default:0;comment:最多同时执行的任务数量"`
+	Priority          int                                    `gorm:"type:int;not null;default:0;comment:优先级"`
+	Namespace         string                                 `gorm:"size:64;not null;comment:命名空间"`
+	CreateAt          time.Time                              `gorm:"type:timestamp;size:6;not null;comment:版本创建时间"`
+}
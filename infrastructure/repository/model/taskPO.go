@@ -24,8 +24,15 @@ type TaskPO struct {
 	Progress    int                                    `gorm:"type:int;not null;comment:进度0-100"`
 	Status      enum.TaskStatus                        `gorm:"type:tinyint;not null;index:idx_status_create,priority:1;index:idx_name_status_create,priority:2;comment:状态"`
 	SchedulerAt time.Time                              `gorm:"type:timestamp;size:6;not null;comment:调度时间"`
-	Data        collections.Dictionary[string, string] `gorm:"type:string;size:2048;serializer:json;not null;comment:本次执行任务时的Data数据"`
+	Data        collections.Dictionary[string, string] `gorm:"type:text;size:0;serializer:json;not null;comment:本次执行任务时的Data数据"`
 	CreateAt    time.Time                              `gorm:"type:timestamp;size:6;not null;index:idx_status_create,priority:2;index:idx_name_create,priority:2;index:idx_name_status_create,priority:3;comment:任务创建时间"`
+	WaitTime    int64                                  `gorm:"type:bigint;not null;default:0;comment:排队等待调度的耗时（毫秒）"`
+	QueueAt     time.Time                              `gorm:"type:timestamp;size:6;comment:进入排队的时间"`
+	Attempt     int                                    `gorm:"type:int;not null;default:0;comment:下发给客户端的次数"`
+	Checkpoint  string                                 `gorm:"type:text;size:0;comment:断点"`
+	Message     string                                 `gorm:"size:256;not null;default:'';comment:最新的进度消息"`
+	IsOverride  bool                                   `gorm:"size:1;not null;default:0;comment:手动执行时覆盖了参数"`
+	IsRestored  bool                                   `gorm:"size:1;not null;default:0;comment:从归档导入（不再清除、归档）"`
 }
 
 // Value return json value, implement driver.Valuer interface
@@ -1,13 +1,146 @@
 package repository
 
 import (
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/parse"
 	"github.com/farseer-go/redis"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const masterKey = "FSchedule_Master"
+
+// 任务组调度锁的前缀
+const scheduleKeyPrefix = "FSchedule_Schedule:"
+
+// Master锁的有效期
+const electionTTL = 20 * time.Second
+
+// Master锁的续约间隔
+const renewInterval = 5 * time.Second
+
+// 未选上Master时，重新尝试的间隔
+const electionInterval = 3 * time.Second
+
+// 任务组分配给的节点
+const assignKey = "FSchedule_Assign"
+
+// 分配给其它节点的任务组，超过该时长仍没有节点抢占时，当前节点再参与抢占
+const assignGrace = 5 * time.Second
+
+// 令牌的前缀，每次抢到锁时递增，用于拒绝已失去锁的节点写入
+const fenceKeyPrefix = "FSchedule_Fence:"
+
+// 抢占：抢到锁时递增并返回令牌，否则返回0
+const acquireScript = `if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then return redis.call("incr", KEYS[2]) else return 0 end`
+
+// 令牌仍是最新的时写入hash（令牌的比较与写入在同一个脚本中），否则返回0
+// KEYS[1]：令牌，KEYS[2..n]：hash；ARGV[1]：持有的令牌，之后每个hash依次为field、value
+const fencedHashSetScript = `if redis.call("get", KEYS[1]) ~= ARGV[1] then return 0 end
+for i = 2, #KEYS do redis.call("hset", KEYS[i], ARGV[i * 2 - 2], ARGV[i * 2 - 1]) end
+return 1`
+
+// 任务下发记录的前缀（幂等键）
+const invokeKeyPrefix = "FSchedule_Invoke:"
+
+// 下发中的记录有效期（下发中断时，之后的下发次数不受影响，只用于清理）
+const invokingTTL = time.Minute
+
+// 下发成功的记录保留时长
+const invokedTTL = 24 * time.Hour
+
+// 按下发次数占用幂等键：已下发成功时返回{ClientId, Attempt}，相同或之后的下发次数正在下发中（上一次下发中断）时返回{0, 下发中的次数}，否则占用并返回0
+const claimInvokeScript = `local clientId = redis.call("hget", KEYS[1], "ClientId")
+if clientId then return {clientId, redis.call("hget", KEYS[1], "Attempt") or "0"} end
+local invoking = redis.call("hget", KEYS[1], "Invoking")
+if invoking and tonumber(invoking) >= tonumber(ARGV[1]) then return {"0", invoking} end
+redis.call("hset", KEYS[1], "Invoking", ARGV[1])
+redis.call("pexpire", KEYS[1], ARGV[2])
+return 0`
+
+// 下发成功：仍由该下发次数占用时，记录下发的客户端
+const completeInvokeScript = `if redis.call("hget", KEYS[1], "Invoking") ~= ARGV[1] then return 0 end
+redis.call("hdel", KEYS[1], "Invoking")
+redis.call("hset", KEYS[1], "TaskId", ARGV[2], "ClientId", ARGV[3], "Attempt", ARGV[1])
+redis.call("pexpire", KEYS[1], ARGV[4])
+return 1`
+
+// 下发失败：只释放该下发次数的占用、下发记录
+const releaseInvokeScript = `if redis.call("hget", KEYS[1], "Invoking") == ARGV[1] then redis.call("hdel", KEYS[1], "Invoking") end
+if redis.call("hget", KEYS[1], "Attempt") == ARGV[1] then redis.call("hdel", KEYS[1], "TaskId", "ClientId", "Attempt") end
+return 1`
+
+// 集群执行中任务的统计：任务所在的客户端、命名空间（Hash），客户端、命名空间下执行中的任务（Set）
+const (
+	workingClientKey       = "FSchedule_Working:Client"
+	workingNamespaceKey    = "FSchedule_Working:Namespace"
+	workingClientPrefix    = "FSchedule_Working:Client:"
+	workingNamespacePrefix = "FSchedule_Working:Namespace:"
+)
+
+// 从任务所在的客户端、命名空间中移除执行中的任务
+const removeWorking = `local clientId = redis.call("hget", KEYS[1], ARGV[1])
+if clientId then redis.call("srem", KEYS[3] .. clientId, ARGV[1]) end
+local namespace = redis.call("hget", KEYS[2], ARGV[1])
+if namespace then redis.call("srem", KEYS[4] .. namespace, ARGV[1]) end
+redis.call("hdel", KEYS[1], ARGV[1])
+redis.call("hdel", KEYS[2], ARGV[1])
+`
+
+// 移除执行中的任务
+const removeWorkingScript = removeWorking + `return 1`
+
+// 添加执行中的任务：先移除旧的统计（重新下发给其它客户端时），再添加
+const addWorkingScript = removeWorking + `redis.call("hset", KEYS[1], ARGV[1], ARGV[2])
+redis.call("sadd", KEYS[3] .. ARGV[2], ARGV[1])
+if ARGV[3] ~= "" then
+	redis.call("hset", KEYS[2], ARGV[1], ARGV[3])
+	redis.call("sadd", KEYS[4] .. ARGV[3], ARGV[1])
+end
+return 1`
+
+// 续约：只有锁仍属于当前节点时才延长有效期
+const renewScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+// 释放：只有锁仍属于指定节点时才删除
+const releaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// 当前节点持有的调度锁（key：调度锁，value：*scheduleLease），失去锁后移除
+var scheduleKeys sync.Map
+
+// 持有的调度锁
+type scheduleLease struct {
+	renewAt atomic.Int64       // 最后一次续约成功的时间（毫秒）
+	fence   int64              // 抢到锁时的令牌
+	ctx     context.Context    // 持有锁期间有效
+	cancel  context.CancelFunc // 失去锁时，取消调度
+}
+
+// 锁仍属于当前节点：未被取消，且在锁过期前预留一个续约间隔（避免本地仍认为持有锁时，锁已被其它节点抢占）
+func (receiver *scheduleLease) isValid() bool {
+	return receiver.ctx.Err() == nil && time.Since(time.UnixMilli(receiver.renewAt.Load())) < electionTTL-renewInterval
+}
+
+// 当前节点作为Master的租约（未当选时为nil）
+var masterLease atomic.Pointer[scheduleLease]
+
+// 节点关闭中，不再参与选举
+var isReleased atomic.Bool
+
+// 有节点释放锁时关闭，唤醒等待中的选举
+var releaseChan = make(chan struct{})
+var releaseLock sync.Mutex
+
 type scheduleRepository struct {
 	redis.IClient `inject:"default"`
 }
@@ -16,14 +149,320 @@ func (receiver *scheduleRepository) ScheduleLock(name string, taskId int64) core
 	return receiver.LockNew("FSchedule_ScheduleLock:"+name+"_"+strconv.FormatInt(taskId, 10), strconv.FormatInt(fs.AppId, 10), 5*time.Second)
 }
 
-func (receiver *scheduleRepository) Election(fn func()) {
-	go receiver.IClient.Election("FSchedule_Master", fn)
+func (receiver *scheduleRepository) Election(fn func(ctx context.Context)) {
+	go func() {
+		appId := strconv.FormatInt(fs.AppId, 10)
+		for !isReleased.Load() {
+			if fence := receiver.acquire(masterKey, appId); fence > 0 {
+				ctx, cancel := context.WithCancel(fs.Context)
+				lease := &scheduleLease{ctx: ctx, cancel: cancel, fence: fence}
+				lease.renewAt.Store(time.Now().UnixMilli())
+				masterLease.Store(lease)
+				fn(ctx)
+				receiver.keepMaster(lease, appId)
+				// 先取消Master相关的任务，再释放锁，避免同时存在两个Master
+				cancel()
+				masterLease.CompareAndSwap(lease, nil)
+				_, _ = receiver.Original().Eval(fs.Context, releaseScript, []string{masterKey}, appId).Int()
+				flog.Warningf("当前节点：%d 失去Master", fs.AppId)
+
+				// 刚失去Master的节点，让其它节点优先竞选
+				time.Sleep(electionTTL / 2)
+				continue
+			}
+			select {
+			case <-time.After(electionInterval):
+			case <-releaseSignal():
+			}
+		}
+	}()
+}
+
+// 给Master锁续约，直到让位、锁不再属于当前节点，或锁过期前仍未续约成功
+func (receiver *scheduleRepository) keepMaster(lease *scheduleLease, appId string) {
+	for {
+		select {
+		case <-lease.ctx.Done():
+			return
+		case <-time.After(renewInterval):
+		}
+		result, err := receiver.Original().Eval(fs.Context, renewScript, []string{masterKey}, appId, electionTTL.Milliseconds()).Int()
+		if err == nil && result > 0 {
+			lease.renewAt.Store(time.Now().UnixMilli())
+			continue
+		}
+		if err == nil || !lease.isValid() {
+			return
+		}
+	}
+}
+
+func (receiver *scheduleRepository) StepDown() bool {
+	lease := masterLease.Load()
+	if lease == nil || lease.ctx.Err() != nil {
+		return false
+	}
+	// 先取消Master相关的任务，再释放锁（由选举协程释放）
+	lease.cancel()
+	return true
 }
 
-func (receiver *scheduleRepository) Schedule(name string, fn func()) {
-	receiver.IClient.Election("FSchedule_Schedule:"+name, fn)
+func (receiver *scheduleRepository) Schedule(name string, fn func(ctx context.Context)) {
+	key := scheduleKeyPrefix + name
+	appId := strconv.FormatInt(fs.AppId, 10)
+	var deferAt time.Time
+	for !isReleased.Load() {
+		// 分配给其它节点的任务组，优先由该节点抢占
+		nodeId, _ := receiver.Original().HGet(fs.Context, assignKey, name).Int64()
+		if nodeId > 0 && nodeId != fs.AppId && deferAt.IsZero() {
+			deferAt = time.Now()
+		}
+		if nodeId <= 0 || nodeId == fs.AppId || time.Since(deferAt) >= assignGrace {
+			if fence := receiver.acquire(key, appId); fence > 0 {
+				ctx, cancel := context.WithCancel(fs.Context)
+				lease := &scheduleLease{ctx: ctx, cancel: cancel, fence: fence}
+				lease.renewAt.Store(time.Now().UnixMilli())
+				scheduleKeys.Store(key, lease)
+				go receiver.keepSchedule(ctx, key, appId, lease)
+				fn(ctx)
+				cancel()
+				return
+			}
+		}
+
+		// 等待锁过期，或其它节点主动释放
+		ttl, _ := receiver.Original().TTL(fs.Context, key).Result()
+		if ttl > 0 {
+			deferAt = time.Time{}
+		} else {
+			ttl = time.Second
+		}
+		select {
+		case <-time.After(ttl):
+		case <-releaseSignal():
+		}
+	}
+}
+
+func (receiver *scheduleRepository) Unschedule(name string) bool {
+	key := scheduleKeyPrefix + name
+	if lease, exists := scheduleKeys.LoadAndDelete(key); exists {
+		lease.(*scheduleLease).cancel()
+	}
+	result, _ := receiver.Original().Eval(fs.Context, releaseScript, []string{key}, strconv.FormatInt(fs.AppId, 10)).Int()
+	return result > 0
+}
+
+func (receiver *scheduleRepository) SaveAssign(assign map[string]int64) {
+	pipe := receiver.Original().TxPipeline()
+	pipe.Del(fs.Context, assignKey)
+	if len(assign) > 0 {
+		values := make(map[string]any, len(assign))
+		for name, nodeId := range assign {
+			values[name] = nodeId
+		}
+		pipe.HSet(fs.Context, assignKey, values)
+	}
+	if _, err := pipe.Exec(fs.Context); err != nil {
+		_ = flog.Error(err)
+	}
+}
+
+func (receiver *scheduleRepository) GetAssign() map[string]int64 {
+	values, _ := receiver.Original().HGetAll(fs.Context, assignKey).Result()
+	assign := make(map[string]int64, len(values))
+	for name, nodeId := range values {
+		assign[name], _ = strconv.ParseInt(nodeId, 10, 64)
+	}
+	return assign
+}
+
+// 给调度锁续约，直到锁被释放或不再属于当前节点（失去锁时移除并取消调度）
+func (receiver *scheduleRepository) keepSchedule(ctx context.Context, key string, appId string, lease *scheduleLease) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(renewInterval):
+		}
+		result, err := receiver.Original().Eval(fs.Context, renewScript, []string{key}, appId, electionTTL.Milliseconds()).Int()
+		if err == nil && result > 0 {
+			lease.renewAt.Store(time.Now().UnixMilli())
+			continue
+		}
+		// redis异常时，超过有效期仍未续约成功，则认为已失去锁
+		if err == nil || !lease.isValid() {
+			flog.Warningf("调度锁：%s 已不属于当前节点", key)
+			scheduleKeys.CompareAndDelete(key, lease)
+			lease.cancel()
+			return
+		}
+	}
+}
+
+// 抢占锁，抢到时返回递增后的令牌，否则返回0
+func (receiver *scheduleRepository) acquire(key string, appId string) int64 {
+	fence, _ := receiver.Original().Eval(fs.Context, acquireScript, []string{key, fenceKeyPrefix + key}, appId, electionTTL.Milliseconds()).Int64()
+	return fence
+}
+
+// 令牌是否仍是最新的（redis异常时拒绝）
+func (receiver *scheduleRepository) isFenceValid(key string, fence int64) bool {
+	curFence, err := receiver.Original().Get(fs.Context, fenceKeyPrefix+key).Int64()
+	return err == nil && curFence == fence
+}
+
+// 当前节点持有的任务组调度锁的令牌（未持有、失去锁、续约超时时返回0）
+func holdingFence(name string) int64 {
+	lease, exists := scheduleKeys.Load(scheduleKeyPrefix + name)
+	if !exists || !lease.(*scheduleLease).isValid() {
+		return 0
+	}
+	return lease.(*scheduleLease).fence
+}
+
+// hash中的一条记录（value以json保存，与CacheManage.SaveItem一致）
+type hashItem struct {
+	key   string
+	field string
+	value any
+}
+
+// 令牌仍是最新的时写入hash，令牌已过期时返回ErrFenced，redis异常时不写入并返回错误
+func hashSetFenced(client redis.IClient, name string, fence int64, items ...hashItem) error {
+	keys := []string{fenceKeyPrefix + scheduleKeyPrefix + name}
+	args := []any{fence}
+	for _, item := range items {
+		value, err := json.Marshal(item.value)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, item.key)
+		args = append(args, item.field, string(value))
+	}
+	result, err := client.Original().Eval(fs.Context, fencedHashSetScript, keys, args...).Int()
+	if err != nil {
+		return fmt.Errorf("任务组：%s 校验令牌失败：%w", name, err)
+	}
+	if result == 0 {
+		return fmt.Errorf("任务组：%s %w", name, taskGroup.ErrFenced)
+	}
+	return nil
+}
+
+func (receiver *scheduleRepository) CheckFence(name string) bool {
+	fence := holdingFence(name)
+	return fence > 0 && receiver.isFenceValid(scheduleKeyPrefix+name, fence)
+}
+
+func (receiver *scheduleRepository) CheckLeader() bool {
+	lease := masterLease.Load()
+	if lease == nil || !lease.isValid() {
+		return false
+	}
+	return receiver.isFenceValid(masterKey, lease.fence)
+}
+
+func (receiver *scheduleRepository) ClaimInvoke(key string, taskId int64, attempt int) (schedule.InvokeVO, bool) {
+	invoke := schedule.InvokeVO{TaskId: taskId, Attempt: attempt}
+	result, err := receiver.Original().Eval(fs.Context, claimInvokeScript, []string{invokeKeyPrefix + key}, attempt, invokingTTL.Milliseconds()).Result()
+	values, isExists := result.([]any)
+	// redis异常时不影响调度
+	if err != nil || !isExists || len(values) < 2 {
+		return invoke, true
+	}
+	invoke.ClientId = parse.Convert(values[0], int64(0))
+	invoke.Attempt = parse.Convert(values[1], 0)
+	return invoke, false
+}
+
+func (receiver *scheduleRepository) CompleteInvoke(key string, invoke schedule.InvokeVO, success bool) {
+	keys := []string{invokeKeyPrefix + key}
+	var err error
+	if success {
+		_, err = receiver.Original().Eval(fs.Context, completeInvokeScript, keys, invoke.Attempt, invoke.TaskId, invoke.ClientId, invokedTTL.Milliseconds()).Result()
+	} else {
+		_, err = receiver.Original().Eval(fs.Context, releaseInvokeScript, keys, invoke.Attempt).Result()
+	}
+	if err != nil {
+		_ = flog.Error(err)
+	}
+}
+
+func (receiver *scheduleRepository) Release() int {
+	isReleased.Store(true)
+	appId := strconv.FormatInt(fs.AppId, 10)
+	count := 0
+	scheduleKeys.Range(func(key, lease any) bool {
+		scheduleKeys.Delete(key)
+		lease.(*scheduleLease).cancel()
+		if result, _ := receiver.Original().Eval(fs.Context, releaseScript, []string{key.(string)}, appId).Int(); result > 0 {
+			count++
+		}
+		// 取消分配，其它节点不需要等待当前节点抢占
+		receiver.Original().HDel(fs.Context, assignKey, strings.TrimPrefix(key.(string), scheduleKeyPrefix))
+		return true
+	})
+	// 当前节点是Master时让位：先取消Master相关的任务，再释放锁
+	if lease := masterLease.Swap(nil); lease != nil {
+		lease.cancel()
+	}
+	_, _ = receiver.Original().Eval(fs.Context, releaseScript, []string{masterKey}, appId).Int()
+	// 唤醒当前节点等待中的抢占，使其退出
+	receiver.NotifyRelease()
+	return count
+}
+
+func (receiver *scheduleRepository) NotifyRelease() {
+	releaseLock.Lock()
+	defer releaseLock.Unlock()
+	close(releaseChan)
+	releaseChan = make(chan struct{})
+}
+
+func releaseSignal() <-chan struct{} {
+	releaseLock.Lock()
+	defer releaseLock.Unlock()
+	return releaseChan
 }
 
 func (receiver *scheduleRepository) GetLeaderId() int64 {
-	return receiver.IClient.GetLeaderId("FSchedule_Master")
+	return receiver.IClient.GetLeaderId(masterKey)
+}
+
+func (receiver *scheduleRepository) IncrDispatch(second int64) int64 {
+	key := "FSchedule_DispatchRate:" + strconv.FormatInt(second, 10)
+	count, err := receiver.Original().Incr(fs.Context, key).Result()
+	if err != nil {
+		_ = flog.Error(err)
+		return 0
+	}
+	if count == 1 {
+		receiver.Original().Expire(fs.Context, key, 2*time.Second)
+	}
+	return count
+}
+
+func (receiver *scheduleRepository) AddWorking(taskId int64, clientId int64, namespace string) {
+	keys := []string{workingClientKey, workingNamespaceKey, workingClientPrefix, workingNamespacePrefix}
+	if err := receiver.Original().Eval(fs.Context, addWorkingScript, keys, taskId, clientId, namespace).Err(); err != nil {
+		_ = flog.Error(err)
+	}
+}
+
+func (receiver *scheduleRepository) RemoveWorking(taskId int64) {
+	keys := []string{workingClientKey, workingNamespaceKey, workingClientPrefix, workingNamespacePrefix}
+	if err := receiver.Original().Eval(fs.Context, removeWorkingScript, keys, taskId).Err(); err != nil {
+		_ = flog.Error(err)
+	}
+}
+
+func (receiver *scheduleRepository) ClientWorkingCount(clientId int64) int {
+	count, _ := receiver.Original().SCard(fs.Context, workingClientPrefix+strconv.FormatInt(clientId, 10)).Result()
+	return int(count)
+}
+
+func (receiver *scheduleRepository) NamespaceWorkingCount(namespace string) int {
+	count, _ := receiver.Original().SCard(fs.Context, workingNamespacePrefix+namespace).Result()
+	return int(count)
 }
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"context"
+	"errors"
+	"github.com/farseer-go/fs/container"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	container.InitContainer()
+	// 只校验本地续约状态，不需要访问redis
+	container.RegisterInstance[schedule.Repository](&scheduleRepository{})
+	os.Exit(m.Run())
+}
+
+// 模拟抢到调度锁
+func holdLease(name string, fence int64) *scheduleLease {
+	ctx, cancel := context.WithCancel(context.Background())
+	lease := &scheduleLease{ctx: ctx, cancel: cancel, fence: fence}
+	lease.renewAt.Store(time.Now().UnixMilli())
+	scheduleKeys.Store(scheduleKeyPrefix+name, lease)
+	return lease
+}
+
+func TestHoldingFence(t *testing.T) {
+	if holdingFence("fence_none") != 0 || (&scheduleRepository{}).CheckFence("fence_none") {
+		t.Fatal("未持有调度锁时应拒绝")
+	}
+
+	lease := holdLease("fence_valid", 7)
+	defer scheduleKeys.Delete(scheduleKeyPrefix + "fence_valid")
+	if holdingFence("fence_valid") != 7 {
+		t.Fatal("持有调度锁时应返回抢到锁时的令牌")
+	}
+
+	// 超过有效期仍未续约成功
+	lease.renewAt.Store(time.Now().Add(-electionTTL).UnixMilli())
+	if holdingFence("fence_valid") != 0 {
+		t.Fatal("续约超时应拒绝")
+	}
+
+	// 失去调度锁
+	lease.renewAt.Store(time.Now().UnixMilli())
+	lease.cancel()
+	if holdingFence("fence_valid") != 0 {
+		t.Fatal("失去调度锁后应拒绝")
+	}
+}
+
+func TestWriteFenced(t *testing.T) {
+	repository := &taskGroupRepository{}
+	item := hashItem{key: taskGroupCacheKey, field: "fence_lost", value: taskGroup.DomainObject{Name: "fence_lost"}}
+	if err := repository.write("fence_lost", true, item); !errors.Is(err, taskGroup.ErrFenced) {
+		t.Fatalf("未持有调度锁时调度线程应返回ErrFenced：%v", err)
+	}
+
+	// 续约超时的调度锁，不再访问redis
+	lease := holdLease("fence_lost", 3)
+	defer scheduleKeys.Delete(scheduleKeyPrefix + "fence_lost")
+	lease.renewAt.Store(time.Now().Add(-electionTTL).UnixMilli())
+	if err := repository.write("fence_lost", true, item); !errors.Is(err, taskGroup.ErrFenced) {
+		t.Fatalf("续约超时时调度线程应返回ErrFenced：%v", err)
+	}
+}
+
+func TestStepDown(t *testing.T) {
+	repository := &scheduleRepository{}
+	if repository.StepDown() {
+		t.Fatal("不是Master时不应让位")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lease := &scheduleLease{ctx: ctx, cancel: cancel}
+	lease.renewAt.Store(time.Now().UnixMilli())
+	masterLease.Store(lease)
+	defer masterLease.Store(nil)
+
+	if !repository.StepDown() {
+		t.Fatal("Master应让位")
+	}
+	// 让位时先取消Master相关的任务，之后不再认为是Master
+	if ctx.Err() == nil || repository.CheckLeader() {
+		t.Fatal("让位后应取消Master的上下文")
+	}
+	if repository.StepDown() {
+		t.Fatal("已让位时不应重复让位")
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"FSchedule/domain/taskEvent"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/data"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/mapper"
+	"github.com/farseer-go/queue"
+	"time"
+)
+
+type TaskEventRepository struct {
+	TaskEvent data.TableSet[model.TaskEventPO] `data:"name=fschedule_task_event"`
+}
+
+func (repository *TaskEventRepository) Add(do taskEvent.DomainObject) {
+	po := mapper.Single[model.TaskEventPO](do)
+	queue.Push("TaskEventQueue", po)
+}
+
+func (repository *TaskEventRepository) ToList(taskId int64) collections.List[taskEvent.DomainObject] {
+	lstPO := repository.TaskEvent.Where("task_id = ?", taskId).Asc("id").ToList()
+	return mapper.ToList[taskEvent.DomainObject](lstPO)
+}
+
+func (repository *TaskEventRepository) RemoveByTaskIds(taskIds []int64) {
+	if len(taskIds) > 0 {
+		repository.TaskEvent.Where("task_id IN ?", taskIds).Delete()
+	}
+}
+
+func (repository *TaskEventRepository) Clear(name string, taskId int64) {
+	repository.TaskEvent.Where("name = ? and task_id < ? and create_at < ?", name, taskId, time.Now().Add(-24*time.Hour)).Delete()
+}
+
+func (repository *TaskEventRepository) AddBatch(lstPO collections.List[model.TaskEventPO]) {
+	err := repository.TaskEvent.InsertList(lstPO, 50)
+	if err != nil {
+		exception.ThrowRefuseException("批量添加报错")
+	}
+}
@@ -4,18 +4,25 @@ import (
 	"FSchedule/domain/enum"
 	"FSchedule/domain/taskGroup"
 	"FSchedule/infrastructure/repository/model"
+	"fmt"
 	"github.com/farseer-go/cache"
 	"github.com/farseer-go/collections"
 	"github.com/farseer-go/data"
 	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/mapper"
 	"github.com/farseer-go/redis"
+	"strconv"
 	"time"
 )
 
+// 任务组缓存（hash，field为任务组名称）
+const taskGroupCacheKey = "FSchedule_TaskGroup"
+
 type taskGroupRepository struct {
 	TaskGroup   data.TableSet[model.TaskGroupPO]           `data:"name=fschedule_task_group"`
+	Version     data.TableSet[model.TaskGroupVersionPO]    `data:"name=fschedule_task_group_version"`
 	Redis       redis.IClient                              `inject:"default"`
 	CacheManage cache.ICacheManage[taskGroup.DomainObject] `inject:"FSchedule_TaskGroup"`
 	*taskRepository
@@ -25,7 +32,7 @@ func registerTaskGroupRepository() {
 	repository := data.NewContext[taskGroupRepository]("default", true)
 	repository.taskRepository = data.NewContext[taskRepository]("default", true)
 
-	repository.CacheManage = redis.SetProfiles[taskGroup.DomainObject]("FSchedule_TaskGroup", "Name", 0, "default")
+	repository.CacheManage = redis.SetProfiles[taskGroup.DomainObject](taskGroupCacheKey, "Name", 0, "default")
 	// 多级缓存
 	repository.CacheManage.SetListSource(func() collections.List[taskGroup.DomainObject] {
 		var lst collections.List[taskGroup.DomainObject]
@@ -49,12 +56,19 @@ func registerTaskGroupRepository() {
 }
 
 func (receiver *taskGroupRepository) Add(do *taskGroup.DomainObject) {
-	po := mapper.Single[model.TaskGroupPO](do)
+	item := *do
+	item.NeedSave = false
+	// Secret参数只保存密文
+	item.ProtectSecret()
+	po := mapper.Single[model.TaskGroupPO](&item)
 	po.ActivateAt = time.Now()
 	po.LastRunAt = time.Now()
 	po.NextAt = time.Now()
 	_ = receiver.TaskGroup.Insert(&po)
-	receiver.CacheManage.SaveItem(*do)
+	receiver.CacheManage.SaveItem(item)
+
+	// 发到所有节点上
+	_ = container.Resolve[core.IEvent]("TaskGroupUpdate").Publish(item)
 }
 
 func (receiver *taskGroupRepository) ToList() collections.List[taskGroup.DomainObject] {
@@ -67,17 +81,71 @@ func (receiver *taskGroupRepository) ToEntity(name string) taskGroup.DomainObjec
 }
 
 func (receiver *taskGroupRepository) Save(do taskGroup.DomainObject) {
-	do.NeedSave = false
-	receiver.CacheManage.SaveItem(do)
-
-	// 发到所有节点上
-	_ = container.Resolve[core.IEvent]("TaskGroupUpdate").Publish(do)
+	if err := receiver.saveGroup(do, false, false); err != nil {
+		flog.Warningf("保存任务组失败：%s", err.Error())
+	}
 }
 
 func (receiver *taskGroupRepository) SaveAndTask(do taskGroup.DomainObject) {
+	if err := receiver.saveGroup(do, true, false); err != nil {
+		flog.Warningf("保存任务组、任务失败：%s", err.Error())
+	}
+}
+
+func (receiver *taskGroupRepository) SaveTask(taskEO taskGroup.TaskEO) {
+	if err := receiver.write(taskEO.Name, false, taskItem(taskEO)); err != nil {
+		flog.Warningf("保存任务失败：%s", err.Error())
+	}
+}
+
+func (receiver *taskGroupRepository) SaveFenced(do taskGroup.DomainObject) error {
+	return receiver.saveGroup(do, false, true)
+}
+
+func (receiver *taskGroupRepository) SaveAndTaskFenced(do taskGroup.DomainObject) error {
+	return receiver.saveGroup(do, true, true)
+}
+
+func (receiver *taskGroupRepository) SaveTaskFenced(taskEO taskGroup.TaskEO) error {
+	return receiver.write(taskEO.Name, true, taskItem(taskEO))
+}
+
+// 保存任务组（withTask：同时保存任务），保存成功后发到所有节点上
+func (receiver *taskGroupRepository) saveGroup(do taskGroup.DomainObject, withTask bool, onlyHolder bool) error {
 	do.NeedSave = false
-	receiver.Save(do)
-	receiver.SaveTask(do.Task)
+	// Secret参数只保存密文
+	do.ProtectSecret()
+	items := []hashItem{{key: taskGroupCacheKey, field: do.Name, value: do}}
+	if withTask {
+		items = append(items, taskItem(do.Task))
+	}
+	if err := receiver.write(do.Name, onlyHolder, items...); err != nil {
+		return err
+	}
+	_ = container.Resolve[core.IEvent]("TaskGroupUpdate").Publish(do)
+	return nil
+}
+
+// 写入缓存：当前节点持有任务组的调度锁时，只有令牌仍是最新的才写入（令牌的比较与写入在同一个脚本中），
+// 未持有时（管理接口、其它节点收到的客户端上报）直接写入；onlyHolder：调度线程写入，未持有调度锁时返回ErrFenced
+func (receiver *taskGroupRepository) write(name string, onlyHolder bool, items ...hashItem) error {
+	if fence := holdingFence(name); fence > 0 {
+		return hashSetFenced(receiver.Redis, name, fence, items...)
+	}
+	if onlyHolder {
+		return fmt.Errorf("任务组：%s %w", name, taskGroup.ErrFenced)
+	}
+	for _, item := range items {
+		if err := receiver.Redis.HashSetEntity(item.key, item.field, item.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 任务在缓存中的记录
+func taskItem(taskEO taskGroup.TaskEO) hashItem {
+	return hashItem{key: taskCacheKeyPrefix + taskEO.Name, field: strconv.FormatInt(taskEO.Id, 10), value: taskEO}
 }
 
 func (receiver *taskGroupRepository) Sync() {
@@ -128,6 +196,23 @@ func (receiver *taskGroupRepository) GetTaskUnFinishList(jobsNames []string, top
 	}).Take(top).ToList()
 }
 
+func (receiver *taskGroupRepository) AddVersion(version taskGroup.VersionEO) {
+	po := mapper.Single[model.TaskGroupVersionPO](version)
+	_ = receiver.Version.UpdateOrInsert(po, "Name", "Ver")
+}
+
+func (receiver *taskGroupRepository) ToVersionList(name string) collections.List[taskGroup.VersionEO] {
+	lstPO := receiver.Version.Where("name = ?", name).Desc("ver").ToList()
+	var lst collections.List[taskGroup.VersionEO]
+	lstPO.MapToList(&lst)
+	return lst
+}
+
+func (receiver *taskGroupRepository) GetVersion(name string, ver int) taskGroup.VersionEO {
+	po := receiver.Version.Where("name = ? and ver = ?", name, ver).ToEntity()
+	return mapper.Single[taskGroup.VersionEO](po)
+}
+
 // SaveToDb 保存到数据库
 func (receiver *taskGroupRepository) SaveToDb(do taskGroup.DomainObject) {
 	po := mapper.Single[model.TaskGroupPO](&do)
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/redis"
+	"strconv"
+	"time"
+)
+
+// 订阅实时日志的任务组（name为空时为所有任务组）
+const liveKeyPrefix = "FSchedule_Live:"
+
+// 订阅记录的有效期，订阅中的节点定时刷新
+const liveTTL = 30 * time.Second
+
+type taskLiveRepository struct {
+	redis.IClient `inject:"default"`
+}
+
+func (receiver *taskLiveRepository) Subscribe(names []string) {
+	pipe := receiver.Original().Pipeline()
+	for _, name := range names {
+		pipe.Set(fs.Context, liveKeyPrefix+name, strconv.FormatInt(fs.AppId, 10), liveTTL)
+	}
+	_, _ = pipe.Exec(fs.Context)
+}
+
+func (receiver *taskLiveRepository) HasSubscriber(name string) bool {
+	count, err := receiver.Original().Exists(fs.Context, liveKeyPrefix+name, liveKeyPrefix).Result()
+	// redis异常时仍推送，不影响实时日志
+	return err != nil || count > 0
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"FSchedule/domain/taskLog"
+	"FSchedule/infrastructure/repository/model"
+	"github.com/farseer-go/data"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// fullTextMode 日志内容的搜索方式
+type fullTextMode int
+
+const (
+	likeSearch     fullTextMode = iota // 没有全文索引，使用like
+	mysqlSearch                        // MySQL FULLTEXT（ngram分词）
+	postgresSearch                     // Postgres tsvector
+	sqliteSearch                       // SQLite FTS5
+)
+
+// 当前使用的搜索方式，启动时根据数据库类型确定
+var taskLogFullText = likeSearch
+
+// InitTaskLogFullText 根据数据库类型，为日志内容创建全文索引（已存在时跳过）
+func InitTaskLogFullText() {
+	// 确保日志表已创建
+	_ = container.Resolve[taskLog.Repository]()
+
+	db, dataType, err := openDb()
+	if err != nil {
+		_ = flog.Errorf("创建日志全文索引时，连接数据库失败：%s", err.Error())
+		return
+	}
+	if db == nil {
+		return
+	}
+	mode := map[string]fullTextMode{"mysql": mysqlSearch, "postgresql": postgresSearch, "sqlite": sqliteSearch}[dataType]
+
+	switch mode {
+	case mysqlSearch:
+		err = createMysqlFullText(db)
+	case postgresSearch:
+		err = db.Exec("CREATE INDEX IF NOT EXISTS idx_task_log_content ON fschedule_task_log USING GIN (to_tsvector('simple', content))").Error
+	case sqliteSearch:
+		err = createSqliteFullText(db)
+	}
+	if err != nil {
+		flog.Warningf("创建日志全文索引失败，搜索日志将使用like：%s", err.Error())
+		return
+	}
+	taskLogFullText = mode
+}
+
+func createMysqlFullText(db *gorm.DB) error {
+	exists := func() bool {
+		var count int64
+		db.Raw("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'fschedule_task_log' AND index_name = 'ft_content'").Scan(&count)
+		return count > 0
+	}
+	if exists() {
+		return nil
+	}
+	// ngram分词，支持中文。多个节点同时启动时，可能已被其它节点创建
+	err := db.Exec("ALTER TABLE fschedule_task_log ADD FULLTEXT INDEX ft_content (content) WITH PARSER ngram").Error
+	if err != nil && exists() {
+		return nil
+	}
+	return err
+}
+
+// SQLite使用外部内容的FTS5表，通过触发器与日志表保持同步（需要以sqlite_fts5标签编译）
+func createSqliteFullText(db *gorm.DB) error {
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'fschedule_task_log_fts'").Scan(&count)
+	if count > 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, sql := range []string{
+			"CREATE VIRTUAL TABLE fschedule_task_log_fts USING fts5(content, content='fschedule_task_log', content_rowid='id')",
+			"CREATE TRIGGER IF NOT EXISTS fschedule_task_log_ai AFTER INSERT ON fschedule_task_log BEGIN INSERT INTO fschedule_task_log_fts(rowid, content) VALUES (new.id, new.content); END",
+			"CREATE TRIGGER IF NOT EXISTS fschedule_task_log_ad AFTER DELETE ON fschedule_task_log BEGIN INSERT INTO fschedule_task_log_fts(fschedule_task_log_fts, rowid, content) VALUES ('delete', old.id, old.content); END",
+			"INSERT INTO fschedule_task_log_fts(fschedule_task_log_fts) VALUES ('rebuild')",
+		} {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 按关键字过滤日志内容
+func whereKeyword(ts *data.TableSet[model.TaskLogPO], keyword string) {
+	// 作为短语匹配，避免关键字中的符号被解析为搜索语法
+	phrase := "\"" + strings.ReplaceAll(keyword, "\"", "\"\"") + "\""
+	switch taskLogFullText {
+	case mysqlSearch:
+		ts.Where("MATCH(content) AGAINST(? IN BOOLEAN MODE)", phrase)
+	case postgresSearch:
+		ts.Where("to_tsvector('simple', content) @@ plainto_tsquery('simple', ?)", keyword)
+	case sqliteSearch:
+		ts.Where("id IN (SELECT rowid FROM fschedule_task_log_fts WHERE fschedule_task_log_fts MATCH ?)", phrase)
+	default:
+		ts.Where("content LIKE ?", "%"+keyword+"%")
+	}
+}
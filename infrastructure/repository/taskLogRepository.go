@@ -9,17 +9,32 @@ import (
 	"github.com/farseer-go/fs/exception"
 	"github.com/farseer-go/mapper"
 	"github.com/farseer-go/queue"
+	"sync/atomic"
+	"time"
 )
 
+// 已推入TaskLogQueue、尚未写入数据库的日志数量
+var taskLogPending atomic.Int64
+
 type TaskLogRepository struct {
 	TaskLog data.TableSet[model.TaskLogPO] `data:"name=fschedule_task_log"`
 }
 
 func (repository *TaskLogRepository) Add(taskLogDO taskLog.DomainObject) {
 	po := mapper.Single[model.TaskLogPO](taskLogDO)
+	taskLogPending.Add(1)
 	queue.Push("TaskLogQueue", po)
 }
 
+func (repository *TaskLogRepository) Flush(timeout time.Duration) bool {
+	for deadline := time.Now().Add(timeout); taskLogPending.Load() > 0; time.Sleep(100 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			return false
+		}
+	}
+	return true
+}
+
 func (repository *TaskLogRepository) GetList(jobName string, logLevel eumLogLevel.Enum, pageSize int, pageIndex int) collections.PageList[taskLog.DomainObject] {
 	pageList := repository.TaskLog.Where("name", jobName).Where("log_level", logLevel).ToPageList(pageSize, pageIndex)
 	var pageListDO collections.PageList[taskLog.DomainObject]
@@ -27,7 +42,100 @@ func (repository *TaskLogRepository) GetList(jobName string, logLevel eumLogLeve
 	return pageListDO
 }
 
+func (repository *TaskLogRepository) ToList(name string, taskId int64, afterId int64, top int) collections.List[taskLog.DomainObject] {
+	ts := repository.TaskLog.Where("name = ?", name)
+	if taskId > 0 {
+		ts.Where("task_id = ?", taskId)
+	}
+	if afterId > 0 {
+		// 增量读取，按时间顺序返回
+		lstPO := ts.Where("id > ?", afterId).Asc("id").Limit(top).ToList()
+		return mapper.ToList[taskLog.DomainObject](lstPO)
+	}
+	// 首次读取，返回最新的N条
+	lstPO := ts.Desc("id").Limit(top).ToList()
+	lstDO := mapper.ToList[taskLog.DomainObject](lstPO)
+	return lstDO.OrderBy(func(item taskLog.DomainObject) any {
+		return item.Id
+	}).ToList()
+}
+
+func (repository *TaskLogRepository) Search(filter taskLog.FilterVO, pageSize int, pageIndex int) collections.PageList[taskLog.DomainObject] {
+	ts := &repository.TaskLog
+	if filter.Name != "" {
+		ts.Where("name = ?", filter.Name)
+	}
+	if filter.TaskId > 0 {
+		ts.Where("task_id = ?", filter.TaskId)
+	}
+	if filter.LogLevel != nil {
+		ts.Where("log_level >= ?", *filter.LogLevel)
+	}
+	if !filter.StartAt.IsZero() {
+		ts.Where("create_at >= ?", filter.StartAt)
+	}
+	if !filter.EndAt.IsZero() {
+		ts.Where("create_at < ?", filter.EndAt)
+	}
+	if filter.Keyword != "" {
+		whereKeyword(ts, filter.Keyword)
+	}
+	pageList := ts.Desc("id").ToPageList(pageSize, pageIndex)
+	var pageListDO collections.PageList[taskLog.DomainObject]
+	pageList.MapToPageList(&pageListDO)
+	return pageListDO
+}
+
+func (repository *TaskLogRepository) ToListByTaskIds(taskIds []int64) collections.List[taskLog.DomainObject] {
+	if len(taskIds) == 0 {
+		return collections.NewList[taskLog.DomainObject]()
+	}
+	lstPO := repository.TaskLog.Where("task_id IN ?", taskIds).Asc("id").ToList()
+	return mapper.ToList[taskLog.DomainObject](lstPO)
+}
+
+func (repository *TaskLogRepository) RemoveByTaskIds(taskIds []int64) {
+	if len(taskIds) > 0 {
+		repository.TaskLog.Where("task_id IN ?", taskIds).Delete()
+	}
+}
+
+func (repository *TaskLogRepository) Import(lstLog collections.List[taskLog.DomainObject]) {
+	for _, do := range lstLog.ToArray() {
+		po := mapper.Single[model.TaskLogPO](do)
+		// 从归档导入的日志，不再被清除、归档
+		po.IsRestored = true
+		_ = repository.TaskLog.UpdateOrInsert(po, "id")
+	}
+}
+
+func (repository *TaskLogRepository) Clear(name string, logLevel eumLogLevel.Enum, before time.Time, top int) int64 {
+	lstPO := repository.TaskLog.Select("id").Where("name = ? and log_level = ? and create_at < ? and is_restored = ?", name, logLevel, before, false).Asc("id").Limit(top).ToArray()
+	return repository.removeByIds(lstPO)
+}
+
+func (repository *TaskLogRepository) ClearExcept(names []string, logLevel eumLogLevel.Enum, before time.Time, top int) int64 {
+	ts := repository.TaskLog.Select("id").Where("log_level = ? and create_at < ? and is_restored = ?", logLevel, before, false)
+	if len(names) > 0 {
+		ts.Where("name NOT IN ?", names)
+	}
+	return repository.removeByIds(ts.Asc("id").Limit(top).ToArray())
+}
+
+// 先查出ID再删除，兼容不支持delete ... limit的数据库
+func (repository *TaskLogRepository) removeByIds(lstPO []model.TaskLogPO) int64 {
+	lstId := make([]int64, 0, len(lstPO))
+	for _, po := range lstPO {
+		lstId = append(lstId, po.Id)
+	}
+	if len(lstId) == 0 {
+		return 0
+	}
+	return repository.TaskLog.Where("id IN ?", lstId).Delete()
+}
+
 func (repository *TaskLogRepository) AddBatch(lstPO collections.List[model.TaskLogPO]) {
+	defer taskLogPending.Add(-int64(lstPO.Count()))
 	err := repository.TaskLog.InsertList(lstPO, 50)
 	if err != nil {
 		exception.ThrowRefuseException("批量添加报错")
@@ -16,6 +16,9 @@ import (
 	"time"
 )
 
+// 任务缓存的前缀（hash，field为任务Id）
+const taskCacheKeyPrefix = "FSchedule_Task:"
+
 var lock = &sync.Mutex{}
 
 type taskRepository struct {
@@ -23,7 +26,7 @@ type taskRepository struct {
 }
 
 func getCacheManager(name string) cache.ICacheManage[taskGroup.TaskEO] {
-	key := "FSchedule_Task:" + name
+	key := taskCacheKeyPrefix + name
 	if !container.IsRegister[cache.ICacheManage[taskGroup.TaskEO]](key) {
 		lock.Lock()
 		defer lock.Unlock()
@@ -48,10 +51,6 @@ func (receiver *taskRepository) GetTask(name string, taskId int64) taskGroup.Tas
 	return item
 }
 
-func (receiver *taskRepository) SaveTask(taskEO taskGroup.TaskEO) {
-	getCacheManager(taskEO.Name).SaveItem(taskEO)
-}
-
 func (receiver *taskRepository) syncTask(name string) {
 	cacheManager := getCacheManager(name)
 	lst := cacheManager.Get()
@@ -85,13 +84,33 @@ func (receiver *taskRepository) ToTaskSpeedList(name string) []int64 {
 }
 
 func (receiver *taskRepository) ToFinishList(name string, top int) collections.List[taskGroup.TaskEO] {
-	lstPO := receiver.Task.Where("name = ? and (status = ? or status = ?)", name, enum.Success, enum.Fail).Desc("create_at").Limit(top).ToList()
+	lstPO := receiver.Task.Where("name = ? and (status = ? or status = ? or status = ?) and is_restored = ?", name, enum.Success, enum.Fail, enum.Skip, false).Desc("create_at").Limit(top).ToList()
+	return mapper.ToList[taskGroup.TaskEO](lstPO)
+}
+
+// ClearFinish 清除成功的任务记录（1天前，从归档导入的除外）
+func (receiver *taskRepository) ClearFinish(name string, taskId int64) {
+	receiver.Task.Where("name = ? and (status = ? or status = ? or status = ?) and create_at < ? and Id < ? and is_restored = ?", name, enum.Success, enum.Fail, enum.Skip, time.Now().Add(-24*time.Hour), taskId, false).Delete()
+}
+
+func (receiver *taskRepository) ToClearList(name string, taskId int64, top int) collections.List[taskGroup.TaskEO] {
+	lstPO := receiver.Task.Where("name = ? and (status = ? or status = ? or status = ?) and create_at < ? and Id < ? and is_restored = ?", name, enum.Success, enum.Fail, enum.Skip, time.Now().Add(-24*time.Hour), taskId, false).Asc("Id").Limit(top).ToList()
 	return mapper.ToList[taskGroup.TaskEO](lstPO)
 }
 
-// ClearFinish 清除成功的任务记录（1天前）
-func (receiver *taskRepository) ClearFinish(name string, taskId int) {
-	receiver.Task.Where("name = ? and (status = ? or status = ?) and create_at < ? and Id < ?", name, enum.Success, enum.Fail, time.Now().Add(-24*time.Hour), taskId).Delete()
+func (receiver *taskRepository) RemoveTasks(taskIds []int64) {
+	if len(taskIds) > 0 {
+		receiver.Task.Where("Id IN ?", taskIds).Delete()
+	}
+}
+
+func (receiver *taskRepository) ImportTasks(lstTask collections.List[taskGroup.TaskEO]) {
+	for _, taskEO := range lstTask.ToArray() {
+		po := mapper.Single[model.TaskPO](&taskEO)
+		// 从归档导入的任务，不再被清除、归档
+		po.IsRestored = true
+		_ = receiver.Task.UpdateOrInsert(po, "Id")
+	}
 }
 
 func (receiver *taskRepository) TodayFailCount() int64 {
@@ -0,0 +1,77 @@
+package secret
+
+import (
+	"FSchedule/domain/secret"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// aesCipher AES-GCM加密，密文格式：enc:<密钥ID>:<base64(nonce+密文)>
+type aesCipher struct {
+	current string                 // 当前加密使用的密钥ID
+	keys    map[string]cipher.AEAD // 所有密钥（包含轮换前的旧密钥，用于解密）
+}
+
+func (receiver *aesCipher) IsEnable() bool {
+	return receiver.current != ""
+}
+
+func (receiver *aesCipher) Encrypt(plain string) (string, error) {
+	if !receiver.IsEnable() {
+		return "", errors.New("未配置密钥：FSchedule.Secret")
+	}
+	aead := receiver.keys[receiver.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return secret.Prefix + receiver.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (receiver *aesCipher) Decrypt(value string) (string, error) {
+	keyId, content, found := strings.Cut(strings.TrimPrefix(value, secret.Prefix), ":")
+	if !secret.IsEncrypted(value) || !found {
+		return "", errors.New("不是加密后的参数")
+	}
+	aead, exists := receiver.keys[keyId]
+	if !exists {
+		return "", fmt.Errorf("密钥：%s 不存在", keyId)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(content)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("密文格式错误")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("使用密钥：%s 解密失败", keyId)
+	}
+	return string(plain), nil
+}
+
+func (receiver *aesCipher) NeedRotate(value string) bool {
+	return receiver.IsEnable() && secret.IsEncrypted(value) && !strings.HasPrefix(value, secret.Prefix+receiver.current+":")
+}
+
+// 添加密钥（base64编码的16、24、32字节，对应AES-128、AES-192、AES-256）
+func (receiver *aesCipher) addKey(keyId string, encodedKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return fmt.Errorf("密钥：%s 不是有效的base64", keyId)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("密钥：%s 长度需为16、24、32字节", keyId)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	receiver.keys[keyId] = aead
+	return nil
+}
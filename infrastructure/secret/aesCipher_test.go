@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"FSchedule/domain/secret"
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, current string, keys map[string]string) *aesCipher {
+	aes := &aesCipher{current: current, keys: make(map[string]cipher.AEAD)}
+	for keyId, key := range keys {
+		if err := aes.addKey(keyId, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return aes
+}
+
+func testKey(size int, fill byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), size)))
+}
+
+func TestAesCipherEncryptDecrypt(t *testing.T) {
+	aes := newTestCipher(t, "k1", map[string]string{"k1": testKey(32, 'a')})
+
+	value, err := aes.Encrypt("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(value, secret.Prefix+"k1:") || strings.Contains(value, "password") {
+		t.Fatalf("密文格式不正确：%s", value)
+	}
+	if plain, err := aes.Decrypt(value); err != nil || plain != "password" {
+		t.Fatalf("解密结果：%s，%v", plain, err)
+	}
+
+	// 每次加密使用随机的nonce
+	if other, _ := aes.Encrypt("password"); other == value {
+		t.Fatal("相同明文两次加密的密文不应相同")
+	}
+}
+
+func TestAesCipherRejectsInvalidValue(t *testing.T) {
+	aes := newTestCipher(t, "k1", map[string]string{"k1": testKey(16, 'a')})
+	value, _ := aes.Encrypt("password")
+
+	// 篡改密文
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secret.Prefix+"k1:"))
+	sealed[len(sealed)-1] ^= 0xff
+	tampered := secret.Prefix + "k1:" + base64.StdEncoding.EncodeToString(sealed)
+
+	for _, invalid := range []string{"password", secret.Prefix + "k1", secret.Prefix + "k2:" + strings.TrimPrefix(value, secret.Prefix+"k1:"), secret.Prefix + "k1:!!!", secret.Prefix + "k1:YWJj", tampered} {
+		if _, err := aes.Decrypt(invalid); err == nil {
+			t.Errorf("%s 应解密失败", invalid)
+		}
+	}
+}
+
+func TestAesCipherRotate(t *testing.T) {
+	old := newTestCipher(t, "k1", map[string]string{"k1": testKey(32, 'a')})
+	value, _ := old.Encrypt("password")
+
+	// 轮换：k2为当前密钥，k1只用于解密
+	rotated := newTestCipher(t, "k2", map[string]string{"k1": testKey(32, 'a'), "k2": testKey(24, 'b')})
+	if !rotated.NeedRotate(value) {
+		t.Fatal("使用旧密钥加密的，需要重新加密")
+	}
+	plain, err := rotated.Decrypt(value)
+	if err != nil || plain != "password" {
+		t.Fatalf("旧密钥的密文应能解密：%s，%v", plain, err)
+	}
+	value, _ = rotated.Encrypt(plain)
+	if !strings.HasPrefix(value, secret.Prefix+"k2:") || rotated.NeedRotate(value) {
+		t.Fatalf("重新加密后应使用当前密钥：%s", value)
+	}
+	if rotated.NeedRotate("password") {
+		t.Fatal("明文不需要轮换")
+	}
+}
+
+func TestAesCipherDisabled(t *testing.T) {
+	aes := newTestCipher(t, "", nil)
+	if aes.IsEnable() || aes.NeedRotate(secret.Prefix+"k1:YWJj") {
+		t.Fatal("未配置密钥时不启用")
+	}
+	if _, err := aes.Encrypt("password"); err == nil {
+		t.Fatal("未配置密钥时加密应失败")
+	}
+}
+
+func TestAesCipherAddKey(t *testing.T) {
+	aes := &aesCipher{keys: make(map[string]cipher.AEAD)}
+	for _, key := range []string{"not base64!", testKey(15, 'a'), testKey(33, 'a')} {
+		if err := aes.addKey("k1", key); err == nil {
+			t.Errorf("密钥：%s 应添加失败", key)
+		}
+	}
+}
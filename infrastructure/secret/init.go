@@ -0,0 +1,79 @@
+package secret
+
+import (
+	"FSchedule/domain/secret"
+	"crypto/cipher"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/parse"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 密钥ID只能包含字母、数字、下划线、中划线
+var keyIdRegexp = regexp.MustCompile(`^[\w-]+$`)
+
+// InitSecret 根据配置（FSchedule.Secret）注册Secret参数的加密方式
+func InitSecret() {
+	aes := &aesCipher{keys: make(map[string]cipher.AEAD)}
+	for keyId, key := range loadKeys() {
+		if !keyIdRegexp.MatchString(keyId) {
+			_ = flog.Errorf("Secret参数：密钥ID：%s 只能包含字母、数字、下划线、中划线", keyId)
+			continue
+		}
+		if err := aes.addKey(keyId, key); err != nil {
+			_ = flog.Errorf("Secret参数：%s", err.Error())
+		}
+	}
+
+	// 多个密钥时，由Current指定加密使用的密钥，其它密钥只用于解密
+	current := configure.GetString("FSchedule.Secret.Current")
+	if current == "" && len(aes.keys) == 1 {
+		for keyId := range aes.keys {
+			current = keyId
+		}
+	}
+	switch {
+	case len(aes.keys) == 0:
+		flog.Warning("Secret参数：未配置密钥（FSchedule.Secret），Secret参数将以明文保存")
+	case aes.keys[current] == nil:
+		_ = flog.Errorf("Secret参数：当前密钥：%s 不存在，Secret参数将以明文保存", current)
+	default:
+		aes.current = current
+		flog.Infof("Secret参数：使用密钥：%s 加密，共%d个密钥", current, len(aes.keys))
+	}
+
+	container.Register(func() secret.Cipher {
+		return aes
+	})
+}
+
+// 读取密钥：FSchedule.Secret.Keys.{ID}，及FSchedule.Secret.KeyFile文件中的{ID}={base64}
+func loadKeys() map[string]string {
+	keys := make(map[string]string)
+	for keyId, key := range configure.GetSubNodes("FSchedule.Secret.Keys") {
+		keys[keyId] = parse.Convert(key, "")
+	}
+
+	keyFile := configure.GetString("FSchedule.Secret.KeyFile")
+	if keyFile == "" {
+		return keys
+	}
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		_ = flog.Errorf("Secret参数：读取密钥文件：%s 失败：%s", keyFile, err.Error())
+		return keys
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if keyId, key, found := strings.Cut(line, "="); found {
+			keys[strings.TrimSpace(keyId)] = strings.TrimSpace(key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"FSchedule/domain/client"
+	"FSchedule/domain/serverNode"
+	"encoding/json"
+	"fmt"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/core"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/utils/http"
+)
+
+const tokenName = "FSS-ACCESS-TOKEN"
+
+var token = configure.GetString("FSchedule.Server.Token")
+
+// clientStream 通过长连接与客户端通讯
+type clientStream struct {
+}
+
+func (receiver clientStream) Check(do *client.DomainObject) (client.ResourceVO, error) {
+	var resource client.ResourceVO
+	body := map[string]any{
+		"clientId": do.Id,
+	}
+	err := receiver.request(do, msgCheck, body, &resource)
+	if err != nil {
+		flog.Warningf("客户端（%d）：长连接检查失败", do.Id)
+	}
+	return resource, err
+}
+
+func (receiver clientStream) Invoke(do *client.DomainObject, task *client.TaskEO) (client.ResourceVO, error) {
+	var resource client.ResourceVO
+	err := receiver.request(do, msgInvoke, task, &resource)
+	return resource, err
+}
+
+func (receiver clientStream) Status(do *client.DomainObject, taskId int64) (client.TaskReportVO, error) {
+	var report client.TaskReportVO
+	body := map[string]any{
+		"TaskId": taskId,
+	}
+	err := receiver.request(do, msgStatus, body, &report)
+	return report, err
+}
+
+func (receiver clientStream) Kill(do *client.DomainObject, taskId int64) bool {
+	var result any
+	body := map[string]any{
+		"TaskId": taskId,
+	}
+	return receiver.request(do, msgKill, body, &result) == nil
+}
+
+// 连接在当前节点则直接发送，否则转发到持有连接的节点
+func (receiver clientStream) request(do *client.DomainObject, msgType string, body any, result any) error {
+	var rsp Message
+	var err error
+	if curSession := sessions.GetValue(do.Id); curSession != nil {
+		rsp, err = curSession.request(msgType, body)
+	} else {
+		rsp, err = forward(do, msgType, body)
+	}
+	if err != nil {
+		return err
+	}
+	if !rsp.IsSuccess() {
+		return flog.Errorf("客户端（%d）：%s，状态码：%d，错误内容：%s", do.Id, msgType, rsp.StatusCode, rsp.StatusMessage)
+	}
+	if len(rsp.Data) > 0 {
+		return json.Unmarshal(rsp.Data, result)
+	}
+	return nil
+}
+
+// 转发到持有长连接的服务端节点
+func forward(do *client.DomainObject, msgType string, body any) (Message, error) {
+	if do.ServerId == fs.AppId {
+		return Message{}, fmt.Errorf("客户端（%d）长连接已断开", do.Id)
+	}
+
+	serverNodeDO := container.Resolve[serverNode.Repository]().ToEntity(do.ServerId)
+	if serverNodeDO.Id == 0 {
+		return Message{}, fmt.Errorf("客户端（%d）所在的服务端节点（%d）不存在", do.Id, do.ServerId)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Message{}, err
+	}
+
+	serverUrl := fmt.Sprintf("http://%s:%d/api/stream/forward", serverNodeDO.Ip, serverNodeDO.Port)
+	dto := ForwardDTO{ClientId: do.Id, Type: msgType, Data: data}
+	var apiResponse core.ApiResponse[Message]
+	if err = http.NewClient(serverUrl).HeadAdd(tokenName, token).Body(dto).PostUnmarshal(&apiResponse); err != nil {
+		return Message{}, err
+	}
+	if apiResponse.StatusCode != 200 {
+		return Message{}, fmt.Errorf("服务端节点（%d）：%s，状态码：%d，错误内容：%s", serverNodeDO.Id, serverUrl, apiResponse.StatusCode, apiResponse.StatusMessage)
+	}
+	return apiResponse.Data, nil
+}
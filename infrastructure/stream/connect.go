@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"encoding/json"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/gorilla/websocket"
+	"net/http"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ForwardDTO 其它节点转发过来的请求
+type ForwardDTO struct {
+	ClientId int64           // 客户端ID
+	Type     string          // 消息类型
+	Data     json.RawMessage // 消息内容
+}
+
+// Connect 客户端建立长连接，连接断开前不会返回
+func Connect(w http.ResponseWriter, r *http.Request, remoteIp string) {
+	if token != "" && r.Header.Get(tokenName) != token {
+		exception.ThrowWebException(403, "token不正确")
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		_ = flog.Errorf("客户端建立长连接失败：%s", err.Error())
+		return
+	}
+	newSession(conn, remoteIp).run()
+}
+
+// Forward 将其它节点转发过来的请求，发送到当前节点持有的长连接
+func Forward(dto ForwardDTO, accessToken string) Message {
+	if token != "" && accessToken != token {
+		exception.ThrowWebException(403, "token不正确")
+	}
+
+	curSession := sessions.GetValue(dto.ClientId)
+	if curSession == nil {
+		exception.ThrowWebExceptionf(404, "客户端（%d）不在当前节点", dto.ClientId)
+	}
+
+	rsp, err := curSession.request(dto.Type, dto.Data)
+	if err != nil {
+		exception.ThrowWebException(500, err.Error())
+	}
+	return rsp
+}
+
+// ClientCount 当前节点持有的长连接数量
+func ClientCount() int {
+	return sessions.Count()
+}
@@ -0,0 +1,14 @@
+package stream
+
+import (
+	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
+	"github.com/farseer-go/fs/container"
+)
+
+// InitStream 初始化客户端长连接
+func InitStream() {
+	container.Register(func() client.IClientCheck {
+		return &clientStream{}
+	}, enum.Stream.String())
+}
@@ -0,0 +1,28 @@
+package stream
+
+import "encoding/json"
+
+const (
+	msgRegistry = "registry" // 客户端注册
+	msgPing     = "ping"     // 客户端心跳
+	msgPong     = "pong"     // 心跳响应
+	msgResponse = "response" // 客户端响应服务端的请求
+	msgCheck    = "check"    // 检查客户端存活
+	msgInvoke   = "invoke"   // 下发任务
+	msgStatus   = "status"   // 查询任务状态
+	msgKill     = "kill"     // 终止任务
+)
+
+// Message 长连接中传输的消息
+type Message struct {
+	Id            int64           // 消息ID（响应时原样返回）
+	Type          string          // 消息类型
+	Data          json.RawMessage // 消息内容
+	StatusCode    int             // 响应状态码（200为成功）
+	StatusMessage string          // 响应内容
+}
+
+// IsSuccess 响应成功
+func (receiver *Message) IsSuccess() bool {
+	return receiver.StatusCode == 200
+}
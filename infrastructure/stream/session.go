@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"FSchedule/application/clientApp"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/enum"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"encoding/json"
+	"fmt"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/snowflake"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+// 超过这个时间没有收到客户端的任何消息，则断开连接
+const readTimeout = 60 * time.Second
+
+// 等待客户端响应的超时时间
+const requestTimeout = 10 * time.Second
+
+// 当前节点持有的客户端长连接
+var sessions = collections.NewDictionary[int64, *session]()
+
+// session 客户端长连接
+type session struct {
+	clientId  int64                                       // 客户端ID（注册后才有值）
+	conn      *websocket.Conn                             // 连接
+	remoteIp  string                                      // 客户端建立连接的来源IP
+	writeLock sync.Mutex                                  // 写锁
+	pending   collections.Dictionary[int64, chan Message] // 等待客户端响应的请求
+}
+
+func newSession(conn *websocket.Conn, remoteIp string) *session {
+	return &session{
+		conn:     conn,
+		remoteIp: remoteIp,
+		pending:  collections.NewDictionary[int64, chan Message](),
+	}
+}
+
+// 读取客户端的消息，直到连接断开
+func (receiver *session) run() {
+	defer receiver.close()
+	for {
+		_ = receiver.conn.SetReadDeadline(time.Now().Add(readTimeout))
+		var msg Message
+		if err := receiver.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case msgResponse:
+			if ch := receiver.pending.GetValue(msg.Id); ch != nil {
+				ch <- msg
+			}
+		case msgPing:
+			_ = receiver.write(Message{Id: msg.Id, Type: msgPong, StatusCode: 200})
+		case msgRegistry:
+			// 注册过程中，需要通过长连接检查客户端，所以不能阻塞读取
+			go receiver.registry(msg)
+		}
+	}
+}
+
+// 客户端注册
+func (receiver *session) registry(msg Message) {
+	rsp := Message{Id: msg.Id, Type: msgResponse, StatusCode: 200}
+	exception.Try(func() {
+		var dto clientApp.RegistryDTO
+		if err := json.Unmarshal(msg.Data, &dto); err != nil {
+			exception.ThrowWebException(403, "注册信息格式错误")
+		}
+		dto.Mode = enum.Stream
+		dto.ServerId = fs.AppId
+
+		// 同一个客户端重复连接时，关闭旧的连接
+		if old := sessions.GetValue(dto.Id); old != nil && old != receiver {
+			_ = old.conn.Close()
+		}
+		receiver.clientId = dto.Id
+		sessions.Add(dto.Id, receiver)
+
+		clientApp.Registry(dto, receiver.remoteIp, container.Resolve[client.Repository](), container.Resolve[taskGroup.Repository](), container.Resolve[schedule.Repository](), container.Resolve[audit.Repository]())
+		flog.Infof("客户端（%d）通过长连接注册：%s", dto.Id, receiver.conn.RemoteAddr().String())
+	}).CatchWebException(func(exp *exception.WebException) {
+		rsp.StatusCode = exp.StatusCode
+		rsp.StatusMessage = exp.Message
+	}).CatchException(func(exp any) {
+		rsp.StatusCode = 500
+		rsp.StatusMessage = fmt.Sprint(exp)
+	})
+	_ = receiver.write(rsp)
+}
+
+// 向客户端发送请求，并等待响应
+func (receiver *session) request(msgType string, body any) (Message, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{Id: snowflake.GenerateId(), Type: msgType, Data: data}
+	ch := make(chan Message, 1)
+	receiver.pending.Add(msg.Id, ch)
+	defer receiver.pending.Remove(msg.Id)
+
+	if err = receiver.write(msg); err != nil {
+		return Message{}, err
+	}
+
+	select {
+	case rsp := <-ch:
+		return rsp, nil
+	case <-time.After(requestTimeout):
+		return Message{}, fmt.Errorf("客户端（%d）响应超时：%s", receiver.clientId, msgType)
+	}
+}
+
+// 发送消息
+func (receiver *session) write(msg Message) error {
+	receiver.writeLock.Lock()
+	defer receiver.writeLock.Unlock()
+	return receiver.conn.WriteJSON(msg)
+}
+
+// 连接断开
+func (receiver *session) close() {
+	_ = receiver.conn.Close()
+	if receiver.clientId == 0 {
+		return
+	}
+
+	// 已被新的连接替换时，不需要下线客户端
+	if sessions.GetValue(receiver.clientId) != receiver {
+		return
+	}
+	sessions.Remove(receiver.clientId)
+
+	flog.Infof("客户端（%d）长连接断开", receiver.clientId)
+	clientApp.Disconnect(receiver.clientId, fs.AppId, container.Resolve[client.Repository]())
+}
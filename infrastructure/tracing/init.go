@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"FSchedule/domain/tracing"
+	"context"
+	"github.com/farseer-go/fs"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	exporterOtlp   = "otlp"   // OTLP/HTTP
+	exporterStdout = "stdout" // 输出到控制台，用于离线调试
+)
+
+// tracingConfig 链路追踪配置（FSchedule.Tracing）
+type tracingConfig struct {
+	Exporter    string  // 导出方式：otlp、stdout（空：不开启）
+	Endpoint    string  // otlp：接收地址，如http://127.0.0.1:4318（为空时使用OTEL_EXPORTER_OTLP_ENDPOINT环境变量）
+	SampleRatio float64 // 采样比例0-1（默认1，上游已采样的链路始终采样）
+}
+
+var provider *sdktrace.TracerProvider
+
+// InitTracing 根据配置开启链路追踪，未开启时Span不会被记录
+func InitTracing() {
+	config := getConfig()
+	otel.SetTextMapPropagator(propagator)
+	// 未开启时同样注册，Span不会被记录，但上游的链路会原样传递给客户端
+	container.Register(func() tracing.Tracer {
+		return otelTracer{}
+	})
+	if config.Exporter == "" {
+		return
+	}
+
+	exporter, err := newExporter(config)
+	if err != nil {
+		_ = flog.Errorf("链路追踪：初始化%s失败：%s", config.Exporter, err.Error())
+		return
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("FSchedule"),
+		semconv.HostName(fs.HostName),
+		attribute.String("fschedule.app_ip", fs.AppIp),
+	)
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	flog.Infof("链路追踪：已开启，导出方式：%s，采样比例：%v", config.Exporter, config.SampleRatio)
+}
+
+// Close 导出剩余的Span
+func Close() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		flog.Warningf("链路追踪：导出剩余的Span失败：%s", err.Error())
+	}
+	provider = nil
+}
+
+func newExporter(config tracingConfig) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case exporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case exporterOtlp:
+		var opts []otlptracehttp.Option
+		if config.Endpoint != "" {
+			endpoint, err := url.Parse(config.Endpoint)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint.Host))
+			if endpoint.Scheme == "http" {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+			if path := strings.TrimRight(endpoint.Path, "/"); path != "" {
+				opts = append(opts, otlptracehttp.WithURLPath(path))
+			}
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	}
+	return nil, flog.Errorf("不支持的导出方式：%s", config.Exporter)
+}
+
+func getConfig() tracingConfig {
+	// 支持连接字符串（Exporter=otlp,Endpoint=http://127.0.0.1:4318）、yaml子节点两种配置方式
+	var config tracingConfig
+	if len(configure.GetSubNodes("FSchedule.Tracing")) > 0 {
+		config = configure.ParseConfig[tracingConfig]("FSchedule.Tracing")
+	} else {
+		config = configure.ParseString[tracingConfig](configure.GetString("FSchedule.Tracing"))
+	}
+	config.Exporter = strings.ToLower(config.Exporter)
+	if config.SampleRatio <= 0 || config.SampleRatio > 1 {
+		config.SampleRatio = 1
+	}
+	return config
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"FSchedule/domain/tracing"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var propagator = propagation.TraceContext{}
+
+// otelTracer 使用OpenTelemetry实现的链路追踪
+type otelTracer struct {
+}
+
+func (receiver otelTracer) Start(traceParent string, name string, attrs ...tracing.Attribute) tracing.Span {
+	ctx := context.Background()
+	if traceParent != "" {
+		ctx = propagator.Extract(ctx, propagation.MapCarrier{tracing.HeaderName: traceParent})
+	}
+	ctx, span := otel.Tracer("FSchedule").Start(ctx, name, trace.WithAttributes(toKeyValues(attrs)...))
+	return &otelSpan{ctx: ctx, span: span}
+}
+
+type otelSpan struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+func (receiver *otelSpan) TraceParent() string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(receiver.ctx, carrier)
+	return carrier.Get(tracing.HeaderName)
+}
+
+func (receiver *otelSpan) SetAttributes(attrs ...tracing.Attribute) {
+	receiver.span.SetAttributes(toKeyValues(attrs)...)
+}
+
+func (receiver *otelSpan) AddEvent(name string, attrs ...tracing.Attribute) {
+	receiver.span.AddEvent(name, trace.WithAttributes(toKeyValues(attrs)...))
+}
+
+func (receiver *otelSpan) Error(err error) {
+	if err != nil {
+		receiver.span.RecordError(err)
+		receiver.span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+func (receiver *otelSpan) End() {
+	receiver.span.End()
+}
+
+func toKeyValues(attrs []tracing.Attribute) []attribute.KeyValue {
+	keyValues := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch value := attr.Value.(type) {
+		case string:
+			keyValues = append(keyValues, attribute.String(attr.Key, value))
+		case int:
+			keyValues = append(keyValues, attribute.Int(attr.Key, value))
+		case int64:
+			keyValues = append(keyValues, attribute.Int64(attr.Key, value))
+		default:
+			keyValues = append(keyValues, attribute.String(attr.Key, fmt.Sprint(value)))
+		}
+	}
+	return keyValues
+}
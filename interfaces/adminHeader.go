@@ -0,0 +1,89 @@
+package interfaces
+
+import (
+	"crypto/subtle"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/parse"
+	"github.com/farseer-go/webapi/context"
+	"net"
+	"strings"
+)
+
+// AdminHeader 管理接口的头部
+type AdminHeader struct {
+	Token string `webapi:"Fss-Access-Token"` // 鉴权token
+	Actor string `webapi:"-"`                // 操作人（由token确定，不读取请求头）
+}
+
+// 校验管理接口的token，并确定操作人
+func (receiver *AdminHeader) check() {
+	receiver.Actor = authenticate(receiver.Token)
+	if receiver.Actor == "" {
+		exception.ThrowWebException(403, "token不正确")
+	}
+}
+
+// 根据token确定操作人：FSchedule.Server.Tokens中配置的操作人，FSchedule.Server.Token为admin（都未配置时不鉴权）
+func authenticate(token string) string {
+	tokens := configure.GetSubNodes("FSchedule.Server.Tokens")
+	for actor, actorToken := range tokens {
+		if value := parse.Convert(actorToken, ""); value != "" && subtle.ConstantTimeCompare([]byte(token), []byte(value)) == 1 {
+			return actor
+		}
+	}
+	serverToken := configure.GetString("FSchedule.Server.Token")
+	if serverToken == "" && len(tokens) == 0 {
+		return "admin"
+	}
+	if serverToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(serverToken)) == 1 {
+		return "admin"
+	}
+	return ""
+}
+
+// 获取请求来源IP：只有请求来自受信任的代理时，才读取X-Forwarded-For、X-Real-Ip
+func remoteIp(httpContext context.HttpContext) string {
+	ip, _, err := net.SplitHostPort(httpContext.URI.RemoteAddr)
+	if err != nil {
+		ip = httpContext.URI.RemoteAddr
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+	// 从右往左，跳过受信任的代理，第一个不受信任的地址即为来源IP
+	if forwarded := httpContext.Header.GetValue("X-Forwarded-For"); forwarded != "" {
+		ips := strings.Split(forwarded, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			if forwardedIp := strings.TrimSpace(ips[i]); forwardedIp != "" && (i == 0 || !isTrustedProxy(forwardedIp)) {
+				return forwardedIp
+			}
+		}
+	}
+	if realIp := httpContext.Header.GetValue("X-Real-Ip"); realIp != "" {
+		return strings.TrimSpace(realIp)
+	}
+	return ip
+}
+
+// 是否为受信任的代理（FSchedule.Server.TrustedProxies，逗号分隔的IP或CIDR）
+func isTrustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, proxy := range strings.Split(configure.GetString("FSchedule.Server.TrustedProxies"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(addr) {
+				return true
+			}
+		} else if proxyIp := net.ParseIP(proxy); proxyIp != nil && proxyIp.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
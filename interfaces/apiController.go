@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"FSchedule/application/clientApp"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// ApiController 客户端注册、下线（/api/registry、/api/logout），记录请求的来源IP用于审计
+type ApiController struct {
+	controller.BaseController
+}
+
+// NewApiController 客户端注册、下线控制器
+func NewApiController() *ApiController {
+	return &ApiController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"Registry": {Method: "POST"},
+				"Logout":   {Method: "POST", Params: "clientId"},
+			},
+		},
+	}
+}
+
+// Registry 客户端注册
+func (receiver *ApiController) Registry(dto clientApp.RegistryDTO, clientRepository client.Repository, taskGroupRepository taskGroup.Repository, scheduleRepository schedule.Repository, auditRepository audit.Repository) {
+	clientApp.Registry(dto, remoteIp(receiver.HttpContext), clientRepository, taskGroupRepository, scheduleRepository, auditRepository)
+}
+
+// Logout 客户端下线
+func (receiver *ApiController) Logout(clientId int64, clientRepository client.Repository, auditRepository audit.Repository) {
+	clientApp.Logout(clientId, remoteIp(receiver.HttpContext), clientRepository, auditRepository)
+}
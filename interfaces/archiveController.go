@@ -0,0 +1,46 @@
+package interfaces
+
+import (
+	"FSchedule/application/archiveApp"
+	"FSchedule/domain/archive"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// ArchiveController 历史任务归档
+type ArchiveController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewArchiveController 历史任务归档控制器
+func NewArchiveController() *ArchiveController {
+	return &ArchiveController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"List":   {Method: "GET", Params: "name"},
+				"Import": {Method: "POST"},
+			},
+		},
+	}
+}
+
+func (receiver *ArchiveController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *ArchiveController) OnActionExecuted() {
+}
+
+// List 任务组的归档
+func (receiver *ArchiveController) List(name string, archiveRepository archive.Repository) collections.List[archive.FileVO] {
+	return archiveApp.List(name, archiveRepository)
+}
+
+// Import 将归档重新导入数据库
+func (receiver *ArchiveController) Import(dto archiveApp.ImportDTO, archiveRepository archive.Repository, taskGroupRepository taskGroup.Repository, taskLogRepository taskLog.Repository, auditRepository audit.Repository) archiveApp.ImportResultDTO {
+	return archiveApp.Import(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), archiveRepository, taskGroupRepository, taskLogRepository, auditRepository)
+}
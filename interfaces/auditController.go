@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"FSchedule/application/auditApp"
+	"FSchedule/domain/audit"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// AuditController 审计记录
+type AuditController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewAuditController 审计记录控制器
+func NewAuditController() *AuditController {
+	return &AuditController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"List": {Method: "POST"},
+			},
+		},
+	}
+}
+
+func (receiver *AuditController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *AuditController) OnActionExecuted() {
+}
+
+// List 查询审计记录
+func (receiver *AuditController) List(dto auditApp.QueryDTO, auditRepository audit.Repository) collections.PageList[audit.DomainObject] {
+	return auditApp.Query(dto, auditRepository)
+}
@@ -0,0 +1,50 @@
+package interfaces
+
+import (
+	"FSchedule/application/clientApp"
+	"FSchedule/domain/client"
+	"FSchedule/domain/taskGroup"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// ClientController 客户端管理
+type ClientController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewClientController 客户端管理控制器
+func NewClientController() *ClientController {
+	return &ClientController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"List":        {Method: "GET"},
+				"Info":        {Method: "GET", Params: "id"},
+				"JobVersions": {Method: "GET"},
+			},
+		},
+	}
+}
+
+func (receiver *ClientController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *ClientController) OnActionExecuted() {
+}
+
+// List 客户端列表
+func (receiver *ClientController) List(clientRepository client.Repository) collections.List[client.DomainObject] {
+	return clientApp.List(clientRepository)
+}
+
+// Info 客户端详情
+func (receiver *ClientController) Info(id int64, clientRepository client.Repository) client.DomainObject {
+	return clientApp.Info(id, clientRepository)
+}
+
+// JobVersions 每个任务组下，客户端注册的版本
+func (receiver *ClientController) JobVersions(clientRepository client.Repository, taskGroupRepository taskGroup.Repository) []clientApp.JobVersionDTO {
+	return clientApp.JobVersions(clientRepository, taskGroupRepository)
+}
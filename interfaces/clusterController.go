@@ -0,0 +1,51 @@
+package interfaces
+
+import (
+	"FSchedule/application/clusterApp"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/serverNode"
+	"github.com/farseer-go/webapi/action"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// ClusterController 集群管理
+type ClusterController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewClusterController 集群管理控制器
+func NewClusterController() *ClusterController {
+	return &ClusterController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"Nodes":    {Method: "GET"},
+				"StepDown": {Method: "POST"},
+				"Metrics":  {Method: "GET"},
+			},
+		},
+	}
+}
+
+func (receiver *ClusterController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *ClusterController) OnActionExecuted() {
+}
+
+// Nodes 集群节点及当前Master
+func (receiver *ClusterController) Nodes(scheduleRepository schedule.Repository, serverNodeRepository serverNode.Repository) clusterApp.NodesDTO {
+	return clusterApp.Nodes(scheduleRepository, serverNodeRepository)
+}
+
+// StepDown 强制当前Master让位
+func (receiver *ClusterController) StepDown(scheduleRepository schedule.Repository, auditRepository audit.Repository) {
+	clusterApp.StepDown(receiver.Header.Actor, remoteIp(receiver.HttpContext), scheduleRepository, auditRepository)
+}
+
+// Metrics 当前节点的调度排队指标（Prometheus文本格式）
+func (receiver *ClusterController) Metrics() action.IResult {
+	return action.Content(clusterApp.Metrics())
+}
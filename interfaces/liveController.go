@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"FSchedule/infrastructure/live"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// 浏览器订阅时，携带token的Cookie
+const liveTokenCookie = "FSS-ACCESS-TOKEN"
+
+// LiveController 实时订阅任务日志、状态变更
+type LiveController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewLiveController 实时订阅控制器
+func NewLiveController() *LiveController {
+	return &LiveController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"Connect": {Method: "GET", Params: "name,taskId"},
+			},
+		},
+	}
+}
+
+func (receiver *LiveController) OnActionExecuting() {
+	// 浏览器的EventSource、WebSocket无法设置请求头，允许通过Cookie传入token（不通过url参数，避免token记录到访问日志）
+	if receiver.Header.Token == "" {
+		if cookie, err := receiver.HttpContext.Request.R.Cookie(liveTokenCookie); err == nil {
+			receiver.Header.Token = cookie.Value
+		}
+	}
+	receiver.Header.check()
+}
+
+func (receiver *LiveController) OnActionExecuted() {
+}
+
+// Connect 订阅任务组（或任务）的日志和状态变更（Server-Sent Events、WebSocket）
+func (receiver *LiveController) Connect(name string, taskId int64) {
+	live.Connect(receiver.HttpContext.Response.W, receiver.HttpContext.Request.R, name, taskId)
+}
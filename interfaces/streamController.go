@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"FSchedule/infrastructure/stream"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// StreamController 客户端长连接
+type StreamController struct {
+	controller.BaseController
+}
+
+// NewStreamController 客户端长连接控制器
+func NewStreamController() *StreamController {
+	return &StreamController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"Connect": {Method: "GET"},
+				"Forward": {Method: "POST"},
+			},
+		},
+	}
+}
+
+// Connect 客户端建立长连接（websocket）
+func (receiver *StreamController) Connect() {
+	stream.Connect(receiver.HttpContext.Response.W, receiver.HttpContext.Request.R, remoteIp(receiver.HttpContext))
+}
+
+// Forward 其它节点转发到当前节点持有的长连接
+func (receiver *StreamController) Forward(dto stream.ForwardDTO) stream.Message {
+	return stream.Forward(dto, receiver.HttpContext.Request.R.Header.Get("FSS-ACCESS-TOKEN"))
+}
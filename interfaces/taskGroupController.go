@@ -0,0 +1,139 @@
+package interfaces
+
+import (
+	"FSchedule/application/taskGroupApp"
+	"FSchedule/domain/audit"
+	"FSchedule/domain/client"
+	"FSchedule/domain/schedule"
+	"FSchedule/domain/taskEvent"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/webapi/action"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// TaskGroupController 任务组管理
+type TaskGroupController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewTaskGroupController 任务组管理控制器
+func NewTaskGroupController() *TaskGroupController {
+	return &TaskGroupController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"List":             {Method: "GET"},
+				"Info":             {Method: "GET", Params: "name"},
+				"Logs":             {Method: "GET", Params: "name,taskId,afterId,top"},
+				"Task":             {Method: "GET", Params: "name,taskId"},
+				"Trigger":          {Method: "POST"},
+				"Kill":             {Method: "POST"},
+				"SetEnable":        {Method: "POST"},
+				"Versions":         {Method: "GET", Params: "name"},
+				"VersionDiff":      {Method: "POST"},
+				"Rollback":         {Method: "POST"},
+				"SetVersionPolicy": {Method: "POST"},
+				"SetCanary":        {Method: "POST"},
+				"SetLogRetention":  {Method: "POST"},
+				"SetData":          {Method: "POST"},
+				"Export":           {Method: "GET", Params: "format"},
+				"Import":           {Method: "POST"},
+				"ReleaseImport":    {Method: "POST", Params: "name"},
+			},
+		},
+	}
+}
+
+func (receiver *TaskGroupController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *TaskGroupController) OnActionExecuted() {
+}
+
+// List 任务组列表
+func (receiver *TaskGroupController) List(taskGroupRepository taskGroup.Repository) collections.List[taskGroup.DomainObject] {
+	return taskGroupApp.List(taskGroupRepository)
+}
+
+// Info 任务组详情
+func (receiver *TaskGroupController) Info(name string, taskGroupRepository taskGroup.Repository) taskGroupApp.InfoDTO {
+	return taskGroupApp.Info(name, taskGroupRepository)
+}
+
+// Logs 任务组的日志
+func (receiver *TaskGroupController) Logs(name string, taskId int64, afterId int64, top int, taskLogRepository taskLog.Repository, taskGroupRepository taskGroup.Repository) collections.List[taskLog.DomainObject] {
+	return taskGroupApp.LogList(name, taskId, afterId, top, taskLogRepository, taskGroupRepository)
+}
+
+// Task 任务详情及状态变更历史
+func (receiver *TaskGroupController) Task(name string, taskId int64, taskGroupRepository taskGroup.Repository, taskEventRepository taskEvent.Repository) taskGroupApp.TaskDTO {
+	return taskGroupApp.Task(name, taskId, taskGroupRepository, taskEventRepository)
+}
+
+// Trigger 立即执行一次
+func (receiver *TaskGroupController) Trigger(dto taskGroupApp.TriggerDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.Trigger(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// Kill 终止执行中的任务
+func (receiver *TaskGroupController) Kill(dto taskGroupApp.NameDTO, taskGroupRepository taskGroup.Repository, clientRepository client.Repository, scheduleRepository schedule.Repository, auditRepository audit.Repository) {
+	taskGroupApp.Kill(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, clientRepository, scheduleRepository, auditRepository)
+}
+
+// SetEnable 开启、停止任务组
+func (receiver *TaskGroupController) SetEnable(dto taskGroupApp.SetEnableDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.SetEnable(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// Versions 任务组的历史定义
+func (receiver *TaskGroupController) Versions(name string, taskGroupRepository taskGroup.Repository) collections.List[taskGroup.VersionEO] {
+	return taskGroupApp.VersionList(name, taskGroupRepository)
+}
+
+// VersionDiff 比较两个版本的定义
+func (receiver *TaskGroupController) VersionDiff(dto taskGroupApp.VersionDiffDTO, taskGroupRepository taskGroup.Repository) []audit.DiffVO {
+	return taskGroupApp.VersionDiff(dto, taskGroupRepository)
+}
+
+// Rollback 回滚到历史版本的定义，并优先调度给该版本的客户端
+func (receiver *TaskGroupController) Rollback(dto taskGroupApp.RollbackDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.Rollback(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// SetVersionPolicy 设置调度客户端版本的策略
+func (receiver *TaskGroupController) SetVersionPolicy(dto taskGroupApp.SetVersionPolicyDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.SetVersionPolicy(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// SetCanary 设置金丝雀发布
+func (receiver *TaskGroupController) SetCanary(dto taskGroupApp.SetCanaryDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.SetCanary(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// SetLogRetention 设置任务日志的保留策略
+func (receiver *TaskGroupController) SetLogRetention(dto taskGroupApp.SetLogRetentionDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.SetLogRetention(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// SetData 修改传给客户端的参数
+func (receiver *TaskGroupController) SetData(dto taskGroupApp.SetDataDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.SetData(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// Export 导出所有任务组的定义（format：yaml、json）
+func (receiver *TaskGroupController) Export(format string, taskGroupRepository taskGroup.Repository) action.IResult {
+	return action.Content(taskGroupApp.Export(format, taskGroupRepository))
+}
+
+// Import 导入任务组的定义，返回与当前集群的差异
+func (receiver *TaskGroupController) Import(dto taskGroupApp.ImportDTO, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) []taskGroupApp.ImportChangeDTO {
+	return taskGroupApp.Import(dto, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
+
+// ReleaseImport 解除任务组的导入管理
+func (receiver *TaskGroupController) ReleaseImport(name string, taskGroupRepository taskGroup.Repository, auditRepository audit.Repository) {
+	taskGroupApp.ReleaseImport(name, receiver.Header.Actor, remoteIp(receiver.HttpContext), taskGroupRepository, auditRepository)
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"FSchedule/application/taskLogApp"
+	"FSchedule/domain/taskGroup"
+	"FSchedule/domain/taskLog"
+	"github.com/farseer-go/collections"
+	"github.com/farseer-go/webapi/controller"
+)
+
+// TaskLogController 任务日志
+type TaskLogController struct {
+	controller.BaseController
+	Header AdminHeader `webapi:"header"`
+}
+
+// NewTaskLogController 任务日志控制器
+func NewTaskLogController() *TaskLogController {
+	return &TaskLogController{
+		BaseController: controller.BaseController{
+			Action: map[string]controller.Action{
+				"Search": {Method: "POST"},
+			},
+		},
+	}
+}
+
+func (receiver *TaskLogController) OnActionExecuting() {
+	receiver.Header.check()
+}
+
+func (receiver *TaskLogController) OnActionExecuted() {
+}
+
+// Search 搜索任务日志（按任务组、任务、级别、时间、关键字过滤）
+func (receiver *TaskLogController) Search(dto taskLogApp.SearchDTO, taskLogRepository taskLog.Repository, taskGroupRepository taskGroup.Repository) collections.PageList[taskLog.DomainObject] {
+	return taskLogApp.Search(dto, taskLogRepository, taskGroupRepository)
+}
@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"FSchedule/domain/tracing"
+	"github.com/farseer-go/webapi/context"
+	"reflect"
+)
+
+// TraceMiddleware 客户端通过请求头传入traceparent时，绑定到入参的TraceParent字段（报文中已带有时以报文为准）
+type TraceMiddleware struct {
+	context.IMiddleware
+}
+
+func (receiver *TraceMiddleware) Invoke(httpContext *context.HttpContext) {
+	if traceParent := httpContext.Request.R.Header.Get(tracing.HeaderName); traceParent != "" {
+		// Route为本次请求的副本，替换Action只影响本次请求
+		httpContext.Route.Action = bindTraceParent(httpContext.Route.Action, traceParent)
+	}
+	receiver.IMiddleware.Invoke(httpContext)
+}
+
+// bindTraceParent 入参解析完成后，将traceParent写入第一个入参（DTO）的TraceParent字段
+func bindTraceParent(action any, traceParent string) any {
+	actionValue := reflect.ValueOf(action)
+	actionType := actionValue.Type()
+	if actionType.Kind() != reflect.Func || actionType.NumIn() == 0 || actionType.In(0).Kind() != reflect.Struct {
+		return action
+	}
+	field, ok := actionType.In(0).FieldByName("TraceParent")
+	if !ok || field.Type.Kind() != reflect.String {
+		return action
+	}
+	return reflect.MakeFunc(actionType, func(args []reflect.Value) []reflect.Value {
+		dto := reflect.New(args[0].Type()).Elem()
+		dto.Set(args[0])
+		if fieldValue := dto.FieldByIndex(field.Index); fieldValue.String() == "" {
+			fieldValue.SetString(traceParent)
+		}
+		args[0] = dto
+		return actionValue.Call(args)
+	}).Interface()
+}
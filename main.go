@@ -1,28 +1,65 @@
 package main
 
 import (
-	"FSchedule/application/clientApp"
+	"FSchedule/application/healthApp"
 	"FSchedule/application/taskGroupApp"
+	"FSchedule/interfaces"
 	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/webapi"
 	"net/http"
 	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
 	fs.Initialize[StartupModule]("FSchedule")
+	// 收到退出信号时，交接任务组后再退出
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+		<-signals
+		fs.Exit(0)
+	}()
+	// 存活、就绪检查及是否为Master（供Kubernetes、负载均衡使用，不需要认证）
+	webapi.RegisterGET("/healthz", healthApp.Healthz)
+	webapi.RegisterGET("/readyz", healthApp.Readyz)
+	webapi.RegisterGET("/cluster", healthApp.Cluster)
+	// 客户端注册、下线（/api/registry、/api/logout）
+	webapi.RegisterController(interfaces.NewApiController())
 	webapi.Area("/api/", func() {
-		// 客户端注册
-		webapi.RegisterPOST("/registry", clientApp.Registry)
-		// 客户端下线
-		webapi.RegisterPOST("/logout", clientApp.Logout)
 		// 客户端回调
 		webapi.RegisterPOST("/taskReport", taskGroupApp.TaskReport)
 		// 上传日志
 		webapi.RegisterPOST("/logReport", taskGroupApp.LogReport)
+		// 保存断点
+		webapi.RegisterPOST("/checkpoint", taskGroupApp.Checkpoint)
+		// 客户端拉取任务
+		webapi.RegisterPOST("/pull", taskGroupApp.Pull)
+		// 客户端长连接
+		webapi.RegisterController(interfaces.NewStreamController())
+	})
+	webapi.Area("/admin/", func() {
+		// 任务组管理
+		webapi.RegisterController(interfaces.NewTaskGroupController())
+		// 客户端管理
+		webapi.RegisterController(interfaces.NewClientController())
+		// 任务日志
+		webapi.RegisterController(interfaces.NewTaskLogController())
+		// 实时订阅任务日志、状态变更
+		webapi.RegisterController(interfaces.NewLiveController())
+		// 集群管理
+		webapi.RegisterController(interfaces.NewClusterController())
+		// 审计记录
+		webapi.RegisterController(interfaces.NewAuditController())
+		// 历史任务归档
+		webapi.RegisterController(interfaces.NewArchiveController())
 	})
 	webapi.UseApiResponse()
+	// 客户端传入的调度链路
+	webapi.RegisterMiddleware(&interfaces.TraceMiddleware{})
 	webapi.UsePprof()
 	webapi.Run()
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"FSchedule/application/healthApp"
 	"FSchedule/infrastructure"
 	"FSchedule/interfaces"
+	"github.com/farseer-go/fs"
 	"github.com/farseer-go/fs/modules"
 )
 
@@ -20,7 +22,8 @@ func (module StartupModule) Initialize() {
 }
 
 func (module StartupModule) PostInitialize() {
-
+	// 最后一个启动步骤，之后就绪检查才会通过
+	fs.AddInitCallback("就绪", healthApp.Ready)
 }
 
 func (module StartupModule) Shutdown() {